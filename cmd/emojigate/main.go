@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/FohkinScroob/emojigate/internal"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
@@ -19,14 +23,22 @@ func main() {
 
 	switch command {
 	case "workflows":
-		lintWorkflowsDirectory()
+		opts := parseLintOptions(command, os.Args[2:])
+		lintWorkflowsDirectory(opts)
 	case "lint":
-		if len(os.Args) < 3 {
+		opts := parseLintOptions(command, os.Args[2:])
+		if len(opts.files) == 0 {
 			fmt.Fprintln(os.Stderr, "Error: 'lint' command requires at least one file argument")
 			printUsage()
 			os.Exit(1)
 		}
-		lintFiles(os.Args[2:])
+		lintFiles(opts.files, opts)
+	case "init":
+		initConfig(os.Args[2:])
+	case "config":
+		configCommand(os.Args[2:])
+	case "emoji":
+		emojiCommand(os.Args[2:])
 	case "help", "-h", "--help":
 		printUsage()
 		os.Exit(0)
@@ -41,13 +53,41 @@ func printUsage() {
 	fmt.Println(`emojigate - Lint GitHub Actions workflows for emoji usage
 
 Usage:
-  emojigate workflows          Lint all workflow files in .github/workflows/
-  emojigate lint <file>...     Lint specific workflow file(s)
-  emojigate help               Show this help message
+  emojigate workflows [flags]          Lint all workflow files in .github/workflows/
+  emojigate lint [flags] <file>...     Lint specific workflow file(s)
+  emojigate init [--force]             Write a .emojigate.yml inferred from existing workflows
+  emojigate config validate [file]     Check a config for unknown keys and invalid values
+  emojigate config print [--file <workflow>]
+                                       Show the effective config and the source of each value
+  emojigate config schema              Print the JSON Schema of .emojigate.yml
+  emojigate emoji search <keyword>...  Find emoji by CLDR name or keyword, or by shortcode
+  emojigate emoji info [flags] <emoji> Describe an emoji and whether the config allows it
+  emojigate emoji legend [flags]       List what each emoji stands for across workflows
+  emojigate help                       Show this help message
+
+Flags:
+  --config <path>          Config file (default: $EMOJIGATE_CONFIG, or .emojigate.yml if present)
+  --set <key>=<value>      Override a config value, e.g. --set rules.require-emoji=warning
+  --levels <list>          Lint only these levels, e.g. --levels workflow,job
+  -v, --verbose            Report skipped files, levels, jobs and steps, and
+                           explain rejected emoji with their code points
+  --fix                    Rewrite workflow files to fix violations where possible
+  --ratchet                Fail only if compliance drops below the recorded floor
+  --update-ratchet         Raise the recorded floor after improvements (implies --ratchet)
+  --ratchet-file <path>    Ratchet state file (default: .emojigate-ratchet.json)
 
 Examples:
   emojigate workflows
+  emojigate workflows --update-ratchet
+  emojigate init
+  emojigate config validate .emojigate.yml
+  emojigate config print --file .github/workflows/ci.yml
   emojigate lint .github/workflows/ci.yml
+  emojigate emoji search rocket
+  emojigate emoji info :rocket:
+  emojigate emoji legend
+  emojigate workflows --fix
+  emojigate workflows --levels workflow,job --verbose
   emojigate lint .github/workflows/ci.yml .github/workflows/release.yml
 
 Pre-commit Hook:
@@ -61,9 +101,131 @@ Pre-commit Hook:
           files: ^\.github/workflows/.*\.ya?ml$`)
 }
 
-func lintWorkflowsDirectory() {
-	workflowsDir := ".github/workflows"
+const workflowsDir = ".github/workflows"
+
+// settingsFlag collects repeated --set key=value flags.
+type settingsFlag []string
+
+func (s *settingsFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *settingsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// configOptions are the flags that select and override the config.
+type configOptions struct {
+	file     string
+	settings settingsFlag
+	levels   string
+}
+
+func (c *configOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.file, "config", "", "config file")
+	fs.Var(&c.settings, "set", "override a config value (key=value)")
+	fs.StringVar(&c.levels, "levels", "", "comma-separated levels to lint (workflow, job, step)")
+}
+
+type lintOptions struct {
+	config        configOptions
+	ratchet       bool
+	updateRatchet bool
+	ratchetFile   string
+	verbose       bool
+	fix           bool
+	files         []string
+	// allWorkflows is set when every workflow is linted, rather than the
+	// files given on the command line.
+	allWorkflows bool
+}
+
+func parseLintOptions(command string, args []string) lintOptions {
+	var opts lintOptions
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	opts.config.register(fs)
+	fs.BoolVar(&opts.ratchet, "ratchet", false, "fail only if compliance drops below the recorded floor")
+	fs.BoolVar(&opts.updateRatchet, "update-ratchet", false, "raise the recorded floor after improvements")
+	fs.StringVar(&opts.ratchetFile, "ratchet-file", internal.DefaultRatchetFile, "ratchet state file")
+	fs.BoolVar(&opts.verbose, "verbose", false, "report skipped files, levels, jobs and steps, and explain rejected emoji")
+	fs.BoolVar(&opts.verbose, "v", false, "shorthand for --verbose")
+	fs.BoolVar(&opts.fix, "fix", false, "rewrite workflow files to fix violations where possible")
+	fs.Usage = printUsage
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	opts.ratchet = opts.ratchet || opts.updateRatchet
+	opts.files = fs.Args()
+
+	return opts
+}
+
+// loadConfigLayers collects every config source: the file given with
+// --config or $EMOJIGATE_CONFIG (or .emojigate.yml when it exists),
+// EMOJIGATE_* environment variables, --levels and --set flags.
+func loadConfigLayers(opts configOptions) *internal.ConfigLayers {
+	layers := internal.NewConfigLayers()
+
+	path := opts.file
+	if path == "" {
+		path = os.Getenv(internal.EnvConfigFile)
+	}
+	if path == "" {
+		if _, err := os.Stat(internal.DefaultConfigFile); err == nil {
+			path = internal.DefaultConfigFile
+		}
+	}
+
+	if path != "" {
+		exitOnConfigError(layers.AddFile(path))
+	}
+	exitOnConfigError(layers.AddEnv(os.Environ()))
+	if opts.levels != "" {
+		exitOnConfigError(layers.AddLevels(strings.Split(opts.levels, ",")))
+	}
+	for _, setting := range opts.settings {
+		exitOnConfigError(layers.AddFlag(setting))
+	}
+
+	return layers
+}
+
+// resolveConfig returns the effective config for a workflow file.
+func resolveConfig(layers *internal.ConfigLayers, file string) *internal.ResolvedConfig {
+	resolved, err := layers.Resolve(file)
+	exitOnConfigError(err)
+
+	return resolved
+}
+
+func exitOnConfigError(err error) {
+	if err == nil {
+		return
+	}
+
+	var invalid *internal.InvalidConfigError
+	if errors.As(err, &invalid) {
+		fmt.Fprintf(os.Stderr, "Error: invalid config %s:\n", invalid.Source)
+		for _, p := range invalid.Problems {
+			if p.Line == 0 {
+				fmt.Fprintf(os.Stderr, "  %s\n", p)
+			} else {
+				fmt.Fprintf(os.Stderr, "  %s:%s\n", invalid.Source, p)
+			}
+		}
+		os.Exit(1)
+	}
 
+	fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+	os.Exit(1)
+}
+
+// findWorkflowFiles returns every .yml and .yaml file in the workflows directory.
+func findWorkflowFiles() []string {
 	if _, err := os.Stat(workflowsDir); os.IsNotExist(err) {
 		fmt.Fprintf(os.Stderr, "Error: directory '%s' does not exist\n", workflowsDir)
 		os.Exit(1)
@@ -86,61 +248,600 @@ func lintWorkflowsDirectory() {
 		}
 	}
 
+	return files
+}
+
+func lintWorkflowsDirectory(opts lintOptions) {
+	files := findWorkflowFiles()
+	opts.allWorkflows = true
+
 	if len(files) == 0 {
 		fmt.Printf("No workflow files found in %s\n", workflowsDir)
 		os.Exit(0)
 	}
 
-	lintFiles(files)
+	lintFiles(files, opts)
+}
+
+func initConfig(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	force := fs.Bool("force", false, "overwrite an existing config")
+	output := fs.String("output", internal.DefaultConfigFile, "config file to write")
+	fs.Usage = printUsage
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(*output); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite)\n", *output)
+		os.Exit(1)
+	}
+
+	var workflows []*yaml.Node
+	for _, file := range findWorkflowFiles() {
+		node, err := internal.ParseYAML(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		workflows = append(workflows, node)
+	}
+
+	conventions := internal.DetectConventions(workflows)
+	if err := os.WriteFile(*output, []byte(conventions.RenderConfig()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Wrote %s from %d workflow file(s)\n", *output, len(workflows))
+}
+
+func configCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: 'config' command requires a subcommand (validate, print, schema)")
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "validate":
+		path := internal.DefaultConfigFile
+		if len(args) > 1 {
+			path = args[1]
+		}
+		validateConfig(path)
+	case "print":
+		printConfig(args[1:])
+	case "schema":
+		schema, err := internal.ConfigJSONSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(schema))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown config subcommand '%s'\n\n", args[0])
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// printConfig shows the effective config for a workflow file and where each
+// value comes from.
+func printConfig(args []string) {
+	var opts configOptions
+
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	opts.register(fs)
+	file := fs.String("file", "", "workflow file to resolve the config for")
+	fs.Usage = printUsage
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	path := ""
+	if *file != "" {
+		path = filepath.ToSlash(filepath.Clean(*file))
+	}
+	resolved := resolveConfig(loadConfigLayers(opts), path)
+
+	if path == "" {
+		fmt.Println("Effective config (no workflow file given, overrides not applied):")
+	} else {
+		fmt.Printf("Effective config for %s:\n", path)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  KEY\tVALUE\tSOURCE")
+	for _, setting := range resolved.Settings() {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if path != "" && resolved.Config.Ignored(path) {
+		fmt.Printf("\n%s is ignored and will not be linted.\n", path)
+	}
+}
+
+func emojiCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: 'emoji' command requires a subcommand (search, info, legend)")
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "search":
+		searchEmoji(args[1:])
+	case "info":
+		emojiInfo(args[1:])
+	case "legend":
+		emojiLegend(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown emoji subcommand '%s'\n\n", args[0])
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// searchEmoji lists the emoji matching every keyword.
+func searchEmoji(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: 'emoji search' requires a keyword")
+		printUsage()
+		os.Exit(1)
+	}
+
+	query := strings.Join(args, " ")
+	results := internal.SearchEmoji(query)
+	if len(results) == 0 {
+		fmt.Printf("No emoji match %q\n", query)
+		os.Exit(1)
+	}
+
+	// The emoji comes last: terminals draw emoji wider than tabwriter counts
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Name, strings.Join(internal.Shortcodes(e), " "), internal.CodePoints(e.Qualified), e.Text)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// emojiInfo describes an emoji given as a literal, code points or shortcode,
+// and checks it against the emoji policy of the effective config.
+func emojiInfo(args []string) {
+	var opts configOptions
+
+	fs := flag.NewFlagSet("emoji info", flag.ExitOnError)
+	opts.register(fs)
+	file := fs.String("file", "", "workflow file to resolve the config for")
+	fs.Usage = printUsage
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Error: 'emoji info' requires an emoji, code points or a :shortcode:")
+		printUsage()
+		os.Exit(1)
+	}
+
+	e, err := internal.LookupEmoji(strings.Join(fs.Args(), " "))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := ""
+	if *file != "" {
+		path = filepath.ToSlash(filepath.Clean(*file))
+	}
+	cfg := resolveConfig(loadConfigLayers(opts), path).Config
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Emoji\t%s\n", e.Qualified)
+	fmt.Fprintf(w, "Name\t%s\n", e.Name)
+	fmt.Fprintf(w, "Code points\t%s\n", internal.CodePoints(e.Qualified))
+	if shortcodes := internal.Shortcodes(e); len(shortcodes) > 0 {
+		fmt.Fprintf(w, "Shortcodes\t%s\n", strings.Join(shortcodes, " "))
+	}
+	if keywords := internal.Keywords(e); len(keywords) > 0 {
+		fmt.Fprintf(w, "Keywords\t%s\n", strings.Join(keywords, ", "))
+	}
+	fmt.Fprintf(w, "Group\t%s / %s\n", e.Group, e.Subgroup)
+	fmt.Fprintf(w, "Emoji version\t%s\n", e.Version)
+	fmt.Fprintf(w, "Presentation\t%s\n", internal.Presentation(e))
+
+	if problems := cfg.CheckEmoji(e); len(problems) == 0 {
+		fmt.Fprintf(w, "Config\t✅ allowed\n")
+	} else {
+		for i, v := range problems {
+			label := ""
+			if i == 0 {
+				label = "Config"
+			}
+			fmt.Fprintf(w, "%s\t❌ %s (%s)\n", label, v.Msg, v.Rule)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// emojiLegend lists the emoji of the workflows with what the names carrying
+// them are about, and flags emoji used for unrelated meanings.
+func emojiLegend(args []string) {
+	var opts configOptions
+
+	fs := flag.NewFlagSet("emoji legend", flag.ExitOnError)
+	opts.register(fs)
+	fs.Usage = printUsage
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	layers := loadConfigLayers(opts)
+	consistency := internal.NewConsistency()
+	for _, file := range findWorkflowFiles() {
+		path := filepath.ToSlash(filepath.Clean(file))
+		cfg := resolveConfig(layers, path).Config
+		if cfg.Ignored(path) {
+			continue
+		}
+
+		node, err := internal.ParseYAML(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		consistency.Add(file, node, cfg)
+	}
+
+	meanings := consistency.Meanings()
+	if len(meanings) == 0 {
+		fmt.Printf("No names with emoji found in %s\n", workflowsDir)
+		return
+	}
+
+	for _, m := range meanings {
+		line := fmt.Sprintf("%s  %d name(s)", m.Emoji.Text, m.Names)
+		if len(m.Topics) > 0 {
+			topics := make([]string, len(m.Topics))
+			for i, topic := range m.Topics {
+				topics[i] = fmt.Sprintf("%s %d", topic.Value, topic.Count)
+			}
+			line += ": " + strings.Join(topics, ", ")
+		}
+		if m.Unrelated {
+			line += "  ⚠️ unrelated meanings"
+		}
+		fmt.Println(line)
+	}
 }
 
-func lintFiles(files []string) {
-	type fileViolations struct {
-		file       string
-		violations []internal.Violation
+func validateConfig(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
 	}
 
+	problems, err := internal.ValidateConfig(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	if len(problems) == 0 {
+		fmt.Printf("✅ %s is valid\n", path)
+		os.Exit(0)
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Found %d problem(s) in %s:\n", len(problems), path)
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "  %s:%s\n", path, p)
+	}
+	os.Exit(1)
+}
+
+type fileViolations struct {
+	file       string
+	violations []internal.Violation
+}
+
+func lintFiles(files []string, opts lintOptions) {
+	layers := loadConfigLayers(opts.config)
+
 	var allViolations []fileViolations
 	totalViolations := 0
+	totalErrors := 0
+	linted := 0
+	compliance := map[string]internal.Compliance{}
+	reports := map[string]*internal.Report{}
+	consistency := internal.NewConsistency()
+	var skipped []string
 
 	for _, file := range files {
+		path := filepath.ToSlash(filepath.Clean(file))
+		cfg := resolveConfig(layers, path).Config
+		if cfg.Ignored(path) {
+			skipped = append(skipped, fmt.Sprintf("%s (matches an ignore pattern)", file))
+			continue
+		}
+		linted++
+		skipped = append(skipped, skippedLevels(file, cfg)...)
+
 		node, err := internal.ParseYAML(file)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", file, err)
 			os.Exit(1)
 		}
 
-		violations, err := internal.LintWorkflow(node)
+		report, err := internal.LintWorkflowReport(node, cfg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", file, err)
 			os.Exit(1)
 		}
 
-		if len(violations) > 0 {
-			allViolations = append(allViolations, fileViolations{
-				file:       file,
-				violations: violations,
-			})
-			totalViolations += len(violations)
+		if opts.fix {
+			report = fixFile(file, cfg, report)
+			if node, err = internal.ParseYAML(file); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", file, err)
+				os.Exit(1)
+			}
+		}
+		consistency.Add(file, node, cfg)
+
+		reports[file] = report
+		for _, skip := range report.Skipped {
+			skipped = append(skipped, fmt.Sprintf("%s [%s] %s (matches levels.%s.ignore pattern %q)",
+				file, skip.Type, skip.Identifier, strings.ToLower(string(skip.Type)), skip.Pattern))
 		}
+
+		allViolations = append(allViolations, fileViolations{file: file})
+	}
+
+	// Consistency across files is only known once every file was linted, and
+	// counts towards the compliance of the names it fails
+	if !opts.allWorkflows {
+		addWorkflowContext(consistency, layers, files)
+	}
+	crossFile := consistency.Violations()
+	var failing []fileViolations
+	for _, fv := range allViolations {
+		report := reports[fv.file]
+		report.AddCrossFile(crossFile[fv.file])
+		fv.violations = report.Violations
+		compliance[filepath.ToSlash(filepath.Clean(fv.file))] = report.Compliance
+		if len(fv.violations) > 0 {
+			failing = append(failing, fv)
+			totalViolations += len(fv.violations)
+			totalErrors += countErrors(fv.violations)
+		}
+	}
+	allViolations = failing
+
+	if opts.verbose {
+		printSkipped(skipped)
+	}
+
+	if opts.ratchet {
+		printViolations(allViolations, opts.verbose)
+		checkRatchet(compliance, opts)
+		return
 	}
 
 	if totalViolations == 0 {
-		fmt.Printf("✅ All %d workflow(s) passed!\n", len(files))
+		fmt.Printf("✅ All %d workflow(s) passed!\n", linted)
+		os.Exit(0)
+	}
+
+	if totalErrors == 0 {
+		printViolations(allViolations, opts.verbose)
+		fmt.Printf("✅ All %d workflow(s) passed with %d warning(s).\n", linted, totalViolations)
 		os.Exit(0)
 	}
 
 	fmt.Fprintf(os.Stderr, "❌ Found %d violation(s) across %d file(s):\n\n", totalViolations, len(allViolations))
+	printViolations(allViolations, opts.verbose)
+
+	fmt.Fprintln(os.Stderr, "❗ Please add an emoji to each workflow, job, and step name.")
+	os.Exit(1)
+}
+
+// addWorkflowContext adds the workflows of the repository that were not given
+// on the command line to consistency, so that cross-file rules compare names
+// with every workflow, as `emojigate workflows` does. Their own violations are
+// not reported, and workflows that cannot be parsed are left out.
+func addWorkflowContext(consistency *internal.Consistency, layers *internal.ConfigLayers, files []string) {
+	if _, err := os.Stat(workflowsDir); err != nil {
+		return
+	}
 
+	given := map[string]bool{}
+	for _, file := range files {
+		if abs, err := filepath.Abs(file); err == nil {
+			given[abs] = true
+		}
+	}
+
+	for _, file := range findWorkflowFiles() {
+		if abs, err := filepath.Abs(file); err != nil || given[abs] {
+			continue
+		}
+
+		path := filepath.ToSlash(filepath.Clean(file))
+		cfg := resolveConfig(layers, path).Config
+		if cfg.Ignored(path) {
+			continue
+		}
+		node, err := internal.ParseYAML(file)
+		if err != nil {
+			continue
+		}
+		consistency.Add(file, node, cfg)
+	}
+}
+
+// maxFixPasses bounds how often a file is fixed and linted again, for fixes
+// that only apply once others have, such as a separator after a shortcode.
+const maxFixPasses = 5
+
+// fixFile applies the fixes of a report to the workflow file, linting it again
+// until no more fixes apply, and returns the report of the fixed file.
+func fixFile(file string, cfg *internal.Config, report *internal.Report) *internal.Report {
+	total := 0
+	for pass := 0; pass < maxFixPasses; pass++ {
+		fixes := internal.Fixes(report.Violations)
+		if len(fixes) == 0 {
+			break
+		}
+
+		source, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+			os.Exit(1)
+		}
+
+		fixed, applied := internal.ApplyFixes(source, fixes)
+		if applied == 0 {
+			break
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(file, fixed, info.Mode().Perm()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		total += applied
+
+		node, err := internal.ParseYAML(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", file, err)
+			os.Exit(1)
+		}
+
+		report, err = internal.LintWorkflowReport(node, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", file, err)
+			os.Exit(1)
+		}
+	}
+
+	if total > 0 {
+		fmt.Printf("🔧 Fixed %d problem(s) in %s\n", total, file)
+	}
+
+	return report
+}
+
+// printViolations reports violations by file. With verbose, each violation
+// is followed by its explanation, if any.
+func printViolations(allViolations []fileViolations, verbose bool) {
 	for _, fv := range allViolations {
 		fmt.Fprintf(os.Stderr, "File: %s\n", fv.file)
 		for _, v := range fv.violations {
 			fmt.Fprintf(os.Stderr, "  [%s] %s\n", v.Type, v.Identifier)
-			fmt.Fprintf(os.Stderr, "    → %s\n", v.Msg)
+			if v.Severity == internal.SeverityWarning {
+				fmt.Fprintf(os.Stderr, "    → warning: %s (%s)\n", v.Msg, v.Rule)
+			} else {
+				fmt.Fprintf(os.Stderr, "    → %s (%s)\n", v.Msg, v.Rule)
+			}
+			if verbose && v.Explanation != "" {
+				fmt.Fprintf(os.Stderr, "      %s\n", v.Explanation)
+			}
 		}
 		fmt.Fprintln(os.Stderr)
 	}
+}
+
+// skippedLevels describes the levels that are not linted in a file.
+func skippedLevels(file string, cfg *internal.Config) []string {
+	var skipped []string
+	for _, t := range []internal.GithubActionType{internal.Workflow, internal.Job, internal.Step} {
+		if !cfg.Level(t).Enabled {
+			level := strings.ToLower(string(t))
+			skipped = append(skipped, fmt.Sprintf("%s [%s] all %s names (levels.%s.enabled is false)", file, t, level, level))
+		}
+	}
+
+	return skipped
+}
+
+func printSkipped(skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Printf("⏭️  Skipped %d item(s):\n", len(skipped))
+	for _, s := range skipped {
+		fmt.Printf("  %s\n", s)
+	}
+	fmt.Println()
+}
+
+func countErrors(violations []internal.Violation) int {
+	errors := 0
+	for _, v := range violations {
+		if v.Severity == internal.SeverityError {
+			errors++
+		}
+	}
+
+	return errors
+}
 
-	fmt.Fprintln(os.Stderr, "❗ Please add an emoji at the beginning of each workflow, job, and step name.")
+// checkRatchet compares the compliance of the linted files against the
+// recorded floor. Violations alone never fail the run in ratchet mode; only a
+// drop in compliance does.
+func checkRatchet(compliance map[string]internal.Compliance, opts lintOptions) {
+	ratchet, err := internal.LoadRatchet(opts.ratchetFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading ratchet %s: %v\n", opts.ratchetFile, err)
+		os.Exit(1)
+	}
+
+	regressions := ratchet.Check(compliance)
+	repository := ratchet.Repository(compliance)
+	fmt.Printf("📈 Compliance: %.1f%% (%d/%d names passing)\n", repository.Percent(), repository.Passing, repository.Names)
+
+	if opts.updateRatchet {
+		ratchet.Raise(compliance)
+		if opts.allWorkflows {
+			for _, file := range ratchet.Prune() {
+				fmt.Printf("🗑️  Dropped the floor of %s, which no longer exists\n", file)
+			}
+		}
+		if err := ratchet.Save(opts.ratchetFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving ratchet %s: %v\n", opts.ratchetFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("💾 Updated ratchet floor in %s\n", opts.ratchetFile)
+	}
+
+	if len(regressions) == 0 {
+		fmt.Println("✅ Compliance did not decrease.")
+		os.Exit(0)
+	}
+
+	fmt.Fprintf(os.Stderr, "❌ Compliance decreased in %d scope(s):\n", len(regressions))
+	for _, r := range regressions {
+		fmt.Fprintf(os.Stderr, "  %s: %.1f%% → %.1f%% (%d/%d names passing)\n",
+			r.Scope, r.Floor.Percent(), r.Current.Percent(), r.Current.Passing, r.Current.Names)
+	}
 	os.Exit(1)
 }
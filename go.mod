@@ -2,4 +2,7 @@ module github.com/FohkinScroob/emojigate
 
 go 1.25
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	golang.org/x/text v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// shortcodesByEmoji maps fully-qualified emoji to their sorted gemoji
+// shortcodes, without colons.
+var shortcodesByEmoji = func() map[string][]string {
+	m := make(map[string][]string)
+	for alias, emoji := range emojiShortcodes {
+		m[emoji] = append(m[emoji], alias)
+	}
+	for _, aliases := range m {
+		slices.Sort(aliases)
+	}
+	return m
+}()
+
+// newEmoji returns the Emoji for a fully-qualified RGI sequence.
+func newEmoji(sequence emojiSequence) Emoji {
+	return Emoji{
+		Text:      sequence.Text,
+		Qualified: sequence.Text,
+		Version:   sequence.Version,
+		Name:      sequence.Name,
+		Group:     sequence.Group,
+		Subgroup:  sequence.Subgroup,
+	}
+}
+
+// LookupEmoji returns the emoji given as a literal emoji (🚀), code points
+// (U+1F680) or a shortcode (:rocket:), the forms emoji.allow and emoji.deny
+// accept for a single emoji.
+func LookupEmoji(s string) (Emoji, error) {
+	pattern, err := parseEmojiPattern(s)
+	if err != nil {
+		return Emoji{}, err
+	}
+	if pattern.sequence == "" {
+		return Emoji{}, fmt.Errorf("%q is an emoji group, not a single emoji", strings.TrimSpace(s))
+	}
+
+	e := newEmoji(rgiByKey[stripVariationSelectors(pattern.sequence)])
+	if literal := strings.TrimSpace(s); literal != "" && stripVariationSelectors(literal) == stripVariationSelectors(e.Qualified) {
+		e.Text = literal
+	}
+
+	return e, nil
+}
+
+// SearchEmoji returns the RGI emoji whose CLDR short name or keywords, or
+// gemoji shortcodes, contain every word of query, in Unicode order with exact name and
+// shortcode matches first. Skin tone variants are left out.
+func SearchEmoji(query string) []Emoji {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(query, ":", " ")))
+	if len(words) == 0 {
+		return nil
+	}
+
+	var exact, partial []Emoji
+	for _, sequence := range rgiEmoji {
+		if stripSkinTones(sequence.Text) != sequence.Text {
+			continue
+		}
+
+		terms := append([]string{sequence.Name}, shortcodesByEmoji[sequence.Text]...)
+		terms = append(terms, emojiKeywords[sequence.Text]...)
+		if !matchesAllWords(terms, words) {
+			continue
+		}
+
+		e := newEmoji(sequence)
+		if isExactMatch(terms[:1+len(shortcodesByEmoji[sequence.Text])], words) {
+			exact = append(exact, e)
+		} else {
+			partial = append(partial, e)
+		}
+	}
+
+	return append(exact, partial...)
+}
+
+// matchesAllWords reports whether every word occurs in one of terms.
+func matchesAllWords(terms, words []string) bool {
+	for _, word := range words {
+		found := false
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(term), word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isExactMatch reports whether one of terms equals the words of a query,
+// ignoring case and the underscores of shortcodes.
+func isExactMatch(terms, words []string) bool {
+	query := strings.Join(words, " ")
+	for _, term := range terms {
+		if strings.EqualFold(strings.ReplaceAll(term, "_", " "), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Shortcodes returns the GitHub shortcodes of an emoji, such as ":rocket:".
+func Shortcodes(e Emoji) []string {
+	aliases := shortcodesByEmoji[e.Qualified]
+	shortcodes := make([]string, len(aliases))
+	for i, alias := range aliases {
+		shortcodes[i] = ":" + alias + ":"
+	}
+	return shortcodes
+}
+
+// Keywords returns the CLDR keywords of an emoji, used by SearchEmoji.
+func Keywords(e Emoji) []string {
+	return emojiKeywords[e.Qualified]
+}
+
+// CodePoints formats the code points of s, such as "U+1F680" or
+// "U+2708 U+FE0F".
+func CodePoints(s string) string {
+	points := make([]string, 0, len(s))
+	for _, r := range s {
+		points = append(points, fmt.Sprintf("U+%04X", r))
+	}
+	return strings.Join(points, " ")
+}
+
+// Presentation describes how an emoji displays without variation selectors.
+// A single code point displays as "text" unless it has the Emoji_Presentation
+// property: ✈ and ☺ need U+FE0F, 🚀 does not. Keycap, flag, modifier and ZWJ
+// sequences always display as "emoji".
+func Presentation(e Emoji) string {
+	base := []rune(stripVariationSelectors(e.Qualified))
+	if len(base) == 1 && !unicode.Is(emojiPresentationTable, base[0]) {
+		return "text"
+	}
+	return "emoji"
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestLookupEmoji tests looking up emoji by literal, code points and shortcode
+func TestLookupEmoji(t *testing.T) {
+	tests := []struct {
+		input     string
+		text      string
+		qualified string
+		name      string
+		wantErr   bool
+	}{
+		{input: "🚀", text: "🚀", qualified: "🚀", name: "rocket"},
+		{input: "✈", text: "✈", qualified: "✈️", name: "airplane"},
+		{input: "U+1F9D1 U+200D U+1F4BB", text: "🧑‍💻", qualified: "🧑‍💻", name: "technologist"},
+		{input: ":rocket:", text: "🚀", qualified: "🚀", name: "rocket"},
+		{input: "Travel & Places", wantErr: true},
+		{input: ":no_such_shortcode:", wantErr: true},
+		{input: "rocket", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			e, err := LookupEmoji(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LookupEmoji(%q) = %+v, want error", tt.input, e)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupEmoji(%q) failed: %v", tt.input, err)
+			}
+			if e.Text != tt.text || e.Qualified != tt.qualified || e.Name != tt.name {
+				t.Errorf("LookupEmoji(%q) = %q (%q, %s), want %q (%q, %s)", tt.input, e.Text, e.Qualified, e.Name, tt.text, tt.qualified, tt.name)
+			}
+		})
+	}
+}
+
+// TestSearchEmoji tests matching names and shortcodes, ranking and skin tones
+func TestSearchEmoji(t *testing.T) {
+	texts := func(emojis []Emoji) []string {
+		var result []string
+		for _, e := range emojis {
+			result = append(result, e.Text)
+		}
+		return result
+	}
+
+	if got := texts(SearchEmoji("rocket")); !slices.Equal(got, []string{"🚀"}) {
+		t.Errorf("SearchEmoji(rocket) = %q, want [🚀]", got)
+	}
+
+	// The :heart: shortcode is an exact match and ranks first
+	hearts := texts(SearchEmoji("heart"))
+	if len(hearts) < 2 || hearts[0] != "❤️" {
+		t.Errorf("SearchEmoji(heart) = %q, want ❤️ first", hearts)
+	}
+
+	// Every word must match
+	if got := texts(SearchEmoji("face hearts")); !slices.Equal(got, []string{"🥰"}) {
+		t.Errorf("SearchEmoji(face hearts) = %q, want [🥰]", got)
+	}
+
+	for _, e := range SearchEmoji("thumbs up") {
+		if stripSkinTones(e.Text) != e.Text {
+			t.Errorf("SearchEmoji(thumbs up) returned skin tone variant %s", e.Text)
+		}
+	}
+
+	if got := SearchEmoji("  "); got != nil {
+		t.Errorf("SearchEmoji(blank) = %q, want nothing", texts(got))
+	}
+}
+
+// TestSearchEmoji_Keywords tests that CLDR keywords match emoji their names do not
+func TestSearchEmoji_Keywords(t *testing.T) {
+	if len(emojiKeywords) == 0 {
+		t.Skip("emoji_tables.go holds no CLDR keywords; regenerate it with go generate ./internal/...")
+	}
+
+	var got []string
+	for _, e := range SearchEmoji("aeroplane") {
+		got = append(got, e.Text)
+	}
+	if !slices.Contains(got, "✈️") {
+		t.Errorf("SearchEmoji(aeroplane) = %q, want ✈️ among them", got)
+	}
+
+	rocket, err := LookupEmoji("🚀")
+	if err != nil {
+		t.Fatalf("LookupEmoji() failed: %v", err)
+	}
+	if keywords := Keywords(rocket); !slices.Contains(keywords, "space") {
+		t.Errorf("Keywords(🚀) = %q, want space among them", keywords)
+	}
+}
+
+// TestEmojiDetails tests code points, shortcodes and presentation of an emoji
+func TestEmojiDetails(t *testing.T) {
+	airplane, err := LookupEmoji("✈")
+	if err != nil {
+		t.Fatalf("LookupEmoji() failed: %v", err)
+	}
+	rocket, err := LookupEmoji("🚀")
+	if err != nil {
+		t.Fatalf("LookupEmoji() failed: %v", err)
+	}
+
+	if got := CodePoints(airplane.Qualified); got != "U+2708 U+FE0F" {
+		t.Errorf("CodePoints(%s) = %q, want %q", airplane.Qualified, got, "U+2708 U+FE0F")
+	}
+	if got := Shortcodes(rocket); !slices.Equal(got, []string{":rocket:"}) {
+		t.Errorf("Shortcodes(🚀) = %q, want [:rocket:]", got)
+	}
+}
+
+// TestPresentation tests the default presentation of single code points and sequences
+func TestPresentation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"🏳️\u200d🌈", "emoji"},
+		{"1️⃣", "emoji"},
+		{"✈️", "text"},
+		{"☺", "text"},
+		{"🚀", "emoji"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			e, err := LookupEmoji(tt.input)
+			if err != nil {
+				t.Fatalf("LookupEmoji(%q) failed: %v", tt.input, err)
+			}
+			if got := Presentation(e); got != tt.expected {
+				t.Errorf("Presentation(%s) = %s, want %s", e.Qualified, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultConfigFile is the repository configuration file read by the CLI.
+const DefaultConfigFile = ".emojigate.yml"
+
+// DefaultSeparator is the text expected between an emoji and the rest of a name.
+const DefaultSeparator = " "
+
+// Config is the linting policy for a repository.
+type Config struct {
+	// Ignore lists glob patterns of workflow files that are not linted.
+	Ignore []string `yaml:"ignore"`
+	// Rules overrides the default severity of individual rules by ID.
+	Rules map[string]Severity `yaml:"rules"`
+	// Levels switches linting of workflow, job and step names on or off.
+	Levels Levels `yaml:"levels"`
+	// Emoji restricts which emoji names may use.
+	Emoji EmojiPolicy `yaml:"emoji"`
+}
+
+// Levels holds the settings of each kind of name.
+type Levels struct {
+	Workflow Level `yaml:"workflow"`
+	Job      Level `yaml:"job"`
+	Step     Level `yaml:"step"`
+}
+
+// Level holds the settings of one kind of name.
+type Level struct {
+	Enabled bool `yaml:"enabled"`
+	// Placement is where names must carry their emoji.
+	Placement Placement `yaml:"placement"`
+	// MaxEmoji is the most emoji a name may contain. Zero allows any number.
+	MaxEmoji int `yaml:"maxEmoji"`
+	// Separator is the text between the emoji and the rest of the name.
+	Separator string `yaml:"separator"`
+	// Semantics ties the emoji of a name to its meaning, see SemanticRule.
+	Semantics []SemanticRule `yaml:"semantics"`
+	// MaxRepeat is how many jobs of a workflow (for jobs) or steps of a job
+	// (for steps) may carry the same emoji. Zero allows any number.
+	MaxRepeat int `yaml:"maxRepeat"`
+	// AllowRepeat lists emoji that any number of jobs or steps may carry, such
+	// as 📥, in the forms of emoji.allow.
+	AllowRepeat []string `yaml:"allowRepeat"`
+	// Ignore lists regular expressions of job IDs (for jobs) or step names
+	// (for steps) that are not linted.
+	Ignore []string `yaml:"ignore"`
+
+	// ignore and allowRepeat are Ignore and AllowRepeat compiled by
+	// Config.compile.
+	ignore      []*regexp.Regexp
+	allowRepeat []emojiPattern
+}
+
+// IgnoredBy returns the first ignore pattern of the level matching value.
+func (l Level) IgnoredBy(value string) (string, bool) {
+	for i, re := range l.ignore {
+		if re.MatchString(value) {
+			return l.Ignore[i], true
+		}
+	}
+
+	return "", false
+}
+
+// AllowsRepeat reports whether sibling names may repeat emoji according to
+// AllowRepeat.
+func (l Level) AllowsRepeat(emoji Emoji) bool {
+	_, ok := matchEmoji(l.AllowRepeat, l.allowRepeat, emoji)
+	return ok
+}
+
+// SemanticRule requires names whose text matches Match, such as
+// "(?i)deploy|release", to carry one of the Emoji, such as 🚀 or 📦.
+type SemanticRule struct {
+	// Match is a regular expression tested against the name without its emoji.
+	Match string `yaml:"match"`
+	// Emoji lists the emoji matching names may use, in the forms of emoji.allow.
+	Emoji []string `yaml:"emoji"`
+
+	// match and emoji are Match and Emoji compiled by Config.compile.
+	match *regexp.Regexp
+	emoji []emojiPattern
+}
+
+// SemanticRule returns the first semantic rule of the level whose pattern
+// matches text.
+func (l Level) SemanticRule(text string) (SemanticRule, bool) {
+	for _, rule := range l.Semantics {
+		if rule.match.MatchString(text) {
+			return rule, true
+		}
+	}
+
+	return SemanticRule{}, false
+}
+
+// Allows reports whether a name carrying emoji satisfies the rule.
+func (r SemanticRule) Allows(emoji Emoji) bool {
+	_, ok := matchEmoji(r.Emoji, r.emoji, emoji)
+	return ok
+}
+
+// EmojiPolicy restricts the emoji names may use. Entries of Allow and
+// Deny are literal emoji, code points (U+1F680), shortcodes (:rocket:) or
+// emoji group and subgroup names ("Food & Drink").
+type EmojiPolicy struct {
+	// Allow lists the only emoji names may use. Empty allows any emoji.
+	Allow []string `yaml:"allow"`
+	// Deny lists emoji names must not use.
+	Deny []string `yaml:"deny"`
+	// MaxVersion is the newest Emoji version names may use, such as "13.0".
+	// Empty allows every version.
+	MaxVersion string `yaml:"maxVersion"`
+	// SkinTone restricts the skin tones of emoji such as 👍🏽.
+	SkinTone SkinTone `yaml:"skinTone"`
+	// GenderNeutral prefers gender-neutral emoji, such as 🧑‍💻, over their
+	// gendered ZWJ variants.
+	GenderNeutral bool `yaml:"genderNeutral"`
+	// Actions maps actions, such as actions/checkout, to the emoji of every
+	// step using them.
+	Actions map[string]string `yaml:"actions"`
+	// Jobs maps job IDs, such as lint, to the emoji of every job with that ID.
+	Jobs map[string]string `yaml:"jobs"`
+
+	// allow and deny are Allow and Deny compiled by Config.compile, and
+	// actions and jobs the emoji of Actions and Jobs by lowercase key.
+	allow   []emojiPattern
+	deny    []emojiPattern
+	actions map[string]emojiPattern
+	jobs    map[string]emojiPattern
+}
+
+// DefaultConfig returns the built-in policy: every rule at its default
+// severity and every level enabled with emoji as prefix, followed by a space.
+func DefaultConfig() *Config {
+	return &Config{
+		Rules: map[string]Severity{},
+		Levels: Levels{
+			Workflow: Level{Enabled: true, Placement: PlacementPrefix, Separator: DefaultSeparator},
+			Job:      Level{Enabled: true, Placement: PlacementPrefix, Separator: DefaultSeparator, MaxRepeat: 1},
+			Step:     Level{Enabled: true, Placement: PlacementPrefix, Separator: DefaultSeparator, MaxRepeat: 1},
+		},
+		Emoji: EmojiPolicy{SkinTone: SkinToneAny},
+	}
+}
+
+// InvalidConfigError is returned when a config source does not match the schema.
+// Source is the config file, "environment" or the offending flag.
+type InvalidConfigError struct {
+	Source   string
+	Problems []ConfigProblem
+}
+
+func (e *InvalidConfigError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		problems = append(problems, p.String())
+	}
+
+	return fmt.Sprintf("invalid config %s: %s", e.Source, strings.Join(problems, "; "))
+}
+
+// LoadConfig reads a single config file on top of the built-in defaults.
+// Overrides are not applied. A config with unknown keys or invalid values is
+// rejected rather than silently ignored.
+func LoadConfig(path string) (*Config, error) {
+	layers := NewConfigLayers()
+	if err := layers.AddFile(path); err != nil {
+		return nil, err
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.Config, nil
+}
+
+// Severity returns the configured severity of a rule, falling back to its default.
+func (c *Config) Severity(rule string) Severity {
+	if severity, ok := c.Rules[rule]; ok {
+		return severity
+	}
+
+	if r, ok := FindRule(rule); ok {
+		return r.Default
+	}
+
+	return SeverityOff
+}
+
+// Level returns the settings of names of type t.
+func (c *Config) Level(t GithubActionType) Level {
+	switch t {
+	case Workflow:
+		return c.Levels.Workflow
+	case Job:
+		return c.Levels.Job
+	default:
+		return c.Levels.Step
+	}
+}
+
+// Allowed reports whether names may use emoji according to emoji.allow.
+func (c *Config) Allowed(emoji Emoji) bool {
+	if len(c.Emoji.Allow) == 0 {
+		return true
+	}
+
+	_, ok := matchEmoji(c.Emoji.Allow, c.Emoji.allow, emoji)
+	return ok
+}
+
+// Denied returns the emoji.deny entry matching emoji.
+func (c *Config) Denied(emoji Emoji) (string, bool) {
+	return matchEmoji(c.Emoji.Deny, c.Emoji.deny, emoji)
+}
+
+// CheckEmoji returns the rules of the emoji policy that emoji breaks, as
+// violations holding only the rule, message and explanation.
+func (c *Config) CheckEmoji(emoji Emoji) []Violation {
+	var violations []Violation
+	described := describeGrapheme(emoji.Text)
+	if !c.Allowed(emoji) {
+		violations = append(violations, Violation{
+			Rule:        RuleAllowedEmoji,
+			Msg:         fmt.Sprintf("Emoji %s is not in the allowed list (emoji.allow)", emoji.Text),
+			Explanation: fmt.Sprintf("%s in %s / %s: disallowed, no emoji.allow entry matches", described, emoji.Group, emoji.Subgroup),
+		})
+	}
+
+	if entry, denied := c.Denied(emoji); denied {
+		violations = append(violations, Violation{
+			Rule:        RuleDeniedEmoji,
+			Msg:         fmt.Sprintf("Emoji %s is denied by emoji.deny entry %q", emoji.Text, entry),
+			Explanation: fmt.Sprintf("%s: disallowed by emoji.deny", described),
+		})
+	}
+
+	if maxVersion := c.Emoji.MaxVersion; maxVersion != "" && newerEmojiVersion(emoji.Version, maxVersion) {
+		violations = append(violations, Violation{
+			Rule:        RuleEmojiVersion,
+			Msg:         fmt.Sprintf("Emoji %s requires Emoji %s, newer than emoji.maxVersion %s", emoji.Text, emoji.Version, maxVersion),
+			Explanation: fmt.Sprintf("%s: too new, added in Emoji %s", described, emoji.Version),
+		})
+	}
+
+	return violations
+}
+
+// matchEmoji returns the first of entries whose compiled pattern matches emoji.
+func matchEmoji(entries []string, patterns []emojiPattern, emoji Emoji) (string, bool) {
+	for i, pattern := range patterns {
+		if pattern.matches(emoji) {
+			return entries[i], true
+		}
+	}
+
+	return "", false
+}
+
+// compile parses the regular expressions and emoji patterns of the config
+// once, rather than for every name they are tested against. The schema
+// already rejects invalid patterns when a config is loaded, so an error here
+// means a config was built with invalid values.
+func (c *Config) compile() error {
+	levels := []struct {
+		key   string
+		level *Level
+	}{
+		{"levels.workflow", &c.Levels.Workflow},
+		{"levels.job", &c.Levels.Job},
+		{"levels.step", &c.Levels.Step},
+	}
+
+	for _, l := range levels {
+		l.level.ignore = make([]*regexp.Regexp, len(l.level.Ignore))
+		for i, pattern := range l.level.Ignore {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s.ignore[%d]: %w", l.key, i, err)
+			}
+			l.level.ignore[i] = re
+		}
+
+		for i := range l.level.Semantics {
+			rule := &l.level.Semantics[i]
+			re, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return fmt.Errorf("%s.semantics[%d].match: %w", l.key, i, err)
+			}
+			rule.match = re
+			if rule.emoji, err = compileEmojiPatterns(fmt.Sprintf("%s.semantics[%d].emoji", l.key, i), rule.Emoji); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if l.level.allowRepeat, err = compileEmojiPatterns(l.key+".allowRepeat", l.level.AllowRepeat); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if c.Emoji.allow, err = compileEmojiPatterns("emoji.allow", c.Emoji.Allow); err != nil {
+		return err
+	}
+	if c.Emoji.deny, err = compileEmojiPatterns("emoji.deny", c.Emoji.Deny); err != nil {
+		return err
+	}
+	if c.Emoji.actions, err = compileEmojiMapping("emoji.actions", c.Emoji.Actions); err != nil {
+		return err
+	}
+	if c.Emoji.jobs, err = compileEmojiMapping("emoji.jobs", c.Emoji.Jobs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func compileEmojiPatterns(key string, entries []string) ([]emojiPattern, error) {
+	patterns := make([]emojiPattern, len(entries))
+	for i, entry := range entries {
+		pattern, err := parseEmojiPattern(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %q: %w", key, i, entry, err)
+		}
+		patterns[i] = pattern
+	}
+
+	return patterns, nil
+}
+
+func compileEmojiMapping(key string, mapping map[string]string) (map[string]emojiPattern, error) {
+	patterns := make(map[string]emojiPattern, len(mapping))
+	for name, entry := range mapping {
+		pattern, err := parseEmojiPattern(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %q: %w", key, name, entry, err)
+		}
+		patterns[strings.ToLower(name)] = pattern
+	}
+
+	return patterns, nil
+}
+
+// Ignored reports whether a workflow file matches one of the ignore patterns.
+// Patterns use path.Match syntax and are matched against the slash-separated path.
+func (c *Config) Ignored(file string) bool {
+	return matchesAny(c.Ignore, file)
+}
@@ -0,0 +1,379 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfig tests reading rule severities and ignore patterns from a config file
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_warnings.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	if got := cfg.Severity(RuleRequireEmoji); got != SeverityWarning {
+		t.Errorf("Severity(%s) = %s, want %s", RuleRequireEmoji, got, SeverityWarning)
+	}
+	if got := cfg.Severity(RuleMissingName); got != SeverityOff {
+		t.Errorf("Severity(%s) = %s, want %s", RuleMissingName, got, SeverityOff)
+	}
+
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{".github/workflows/generated-docs.yml", true},
+		{".github/workflows/ci.yml", false},
+		{".github/workflows/nested/generated-docs.yml", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Ignored(tt.file); got != tt.expected {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.file, got, tt.expected)
+		}
+	}
+}
+
+// TestLoadConfig_InvalidFile tests that unreadable and malformed configs are errors
+func TestLoadConfig_InvalidFile(t *testing.T) {
+	if _, err := LoadConfig("testdata/non_existent_config.yml"); err == nil {
+		t.Error("LoadConfig() should return error for non-existent file")
+	}
+
+	tmpFile := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(tmpFile, []byte("rules: [unclosed\n"), 0644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	if _, err := LoadConfig(tmpFile); err == nil {
+		t.Error("LoadConfig() should return error for invalid YAML")
+	}
+}
+
+// TestDefaultConfig_Severity tests that unset rules fall back to their defaults
+func TestDefaultConfig_Severity(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, rule := range Rules {
+		if got := cfg.Severity(rule.ID); got != rule.Default {
+			t.Errorf("Severity(%s) = %s, want default %s", rule.ID, got, rule.Default)
+		}
+	}
+	if got := cfg.Severity("no-such-rule"); got != SeverityOff {
+		t.Errorf("Severity(unknown) = %s, want %s", got, SeverityOff)
+	}
+}
+
+// TestConfig_Compile tests that invalid patterns of a config built in code are errors, not skipped
+func TestConfig_Compile(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(cfg *Config)
+		error  string
+	}{
+		{
+			name: "valid",
+			config: func(cfg *Config) {
+				cfg.Levels.Step.Ignore = []string{"^Checkout"}
+				cfg.Levels.Job.Semantics = []SemanticRule{{Match: "(?i)deploy", Emoji: []string{"🚀", ":package:"}}}
+				cfg.Emoji.Deny = []string{"Food & Drink"}
+				cfg.Emoji.Actions = map[string]string{"actions/checkout": "U+1F4E5"}
+			},
+		},
+		{
+			name:   "ignore",
+			config: func(cfg *Config) { cfg.Levels.Step.Ignore = []string{"^ok$", "(unclosed"} },
+			error:  "levels.step.ignore[1]: error parsing regexp",
+		},
+		{
+			name:   "semantics match",
+			config: func(cfg *Config) { cfg.Levels.Job.Semantics = []SemanticRule{{Match: "*"}} },
+			error:  "levels.job.semantics[0].match: error parsing regexp",
+		},
+		{
+			name: "semantics emoji",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Semantics = []SemanticRule{{Match: "deploy", Emoji: []string{":no_such_shortcode:"}}}
+			},
+			error: `levels.job.semantics[0].emoji[0]: ":no_such_shortcode:": unknown shortcode`,
+		},
+		{
+			name:   "allowRepeat",
+			config: func(cfg *Config) { cfg.Levels.Step.AllowRepeat = []string{"x"} },
+			error:  `levels.step.allowRepeat[0]: "x": not an emoji`,
+		},
+		{
+			name:   "deny",
+			config: func(cfg *Config) { cfg.Emoji.Deny = []string{"🚀", "U+ZZZ"} },
+			error:  `emoji.deny[1]: "U+ZZZ": invalid code point`,
+		},
+		{
+			name:   "jobs",
+			config: func(cfg *Config) { cfg.Emoji.Jobs = map[string]string{"lint": "lint"} },
+			error:  `emoji.jobs.lint: "lint": not an emoji`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.config(cfg)
+
+			err := cfg.compile()
+			if tt.error == "" {
+				if err != nil {
+					t.Fatalf("compile() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.error) {
+				t.Errorf("compile() error = %v, want %q", err, tt.error)
+			}
+		})
+	}
+}
+
+// TestLevel_IgnoredBy tests that a loaded config matches job IDs and step names against its
+// compiled ignore patterns, reporting the first one that matches
+func TestLevel_IgnoredBy(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_levels.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	tests := []struct {
+		level   GithubActionType
+		value   string
+		pattern string
+		ignored bool
+	}{
+		{Job, "test", "^test$", true},
+		{Job, "integration-test", "", false},
+		{Step, "Checkout go", "^Checkout", true},
+		{Step, "Set up Go", "(?i)go$", true},
+		{Step, "Build", "", false},
+		{Workflow, "test", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.level, tt.value), func(t *testing.T) {
+			pattern, ignored := cfg.Level(tt.level).IgnoredBy(tt.value)
+			if pattern != tt.pattern || ignored != tt.ignored {
+				t.Errorf("IgnoredBy(%q) = %q, %v, want %q, %v", tt.value, pattern, ignored, tt.pattern, tt.ignored)
+			}
+		})
+	}
+}
+
+// TestLintWorkflowReport_Severities tests that configured severities are applied to violations
+func TestLintWorkflowReport_Severities(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_warnings.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	node, err := ParseYAML("testdata/invalid_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	if len(report.Violations) != 7 {
+		t.Errorf("Expected 7 violations, got %d", len(report.Violations))
+	}
+	for _, v := range report.Violations {
+		if v.Severity != SeverityWarning {
+			t.Errorf("[%s] %s severity = %s, want %s", v.Type, v.Identifier, v.Severity, SeverityWarning)
+		}
+	}
+
+	// Warnings do not fail a name, so every name counts as passing
+	if report.Compliance.Passing != report.Compliance.Names {
+		t.Errorf("Compliance = %+v, want all names passing", report.Compliance)
+	}
+
+	node, err = ParseYAML("testdata/missing_workflow_name.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err = LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+	if len(report.Violations) != 0 {
+		t.Errorf("Expected disabled missing-name rule to report nothing, got %d violations", len(report.Violations))
+	}
+}
+
+// TestLintWorkflowReport_Levels tests that disabled levels and ignored jobs and steps are not linted
+func TestLintWorkflowReport_Levels(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_levels.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	node, err := ParseYAML("testdata/invalid_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	// Only the build job name and its 🏗️ Build step are linted
+	if len(report.Violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %+v", len(report.Violations), report.Violations)
+	}
+	if v := report.Violations[0]; v.Type != Job || v.Identifier != "build" {
+		t.Errorf("violation = [%s] %s, want [%s] build", v.Type, v.Identifier, Job)
+	}
+	if report.Compliance != (Compliance{Names: 2, Passing: 1}) {
+		t.Errorf("Compliance = %+v, want 1/2", report.Compliance)
+	}
+
+	expectedSkipped := []Skipped{
+		{Type: Step, Identifier: "Checkout code", Pattern: "^Checkout"},
+		{Type: Step, Identifier: "Setup Go", Pattern: "(?i)go$"},
+		{Type: Job, Identifier: "test", Pattern: "^test$"},
+	}
+	if len(report.Skipped) != len(expectedSkipped) {
+		t.Fatalf("Skipped = %+v, want %+v", report.Skipped, expectedSkipped)
+	}
+	for i, s := range report.Skipped {
+		if s != expectedSkipped[i] {
+			t.Errorf("skipped %d = %+v, want %+v", i, s, expectedSkipped[i])
+		}
+	}
+}
+
+// TestLintWorkflowReport_MaxVersion tests that emoji newer than emoji.maxVersion are reported
+func TestLintWorkflowReport_MaxVersion(t *testing.T) {
+	tests := []struct {
+		maxVersion string
+		expected   []string
+	}{
+		{"", nil},
+		{"15.1", nil},
+		{"14", []string{"Emoji 🐦\u200d🔥 requires Emoji 15.1, newer than emoji.maxVersion 14"}},
+		{"13.0", []string{
+			"Emoji 🫠 requires Emoji 14.0, newer than emoji.maxVersion 13.0",
+			"Emoji 🐦\u200d🔥 requires Emoji 15.1, newer than emoji.maxVersion 13.0",
+		}},
+	}
+
+	node, err := ParseYAML("testdata/newer_emoji.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.maxVersion, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Emoji.MaxVersion = tt.maxVersion
+
+			report, err := LintWorkflowReport(node, cfg)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			if len(report.Violations) != len(tt.expected) {
+				t.Fatalf("Expected %d violations, got %d: %+v", len(tt.expected), len(report.Violations), report.Violations)
+			}
+			for i, v := range report.Violations {
+				if v.Msg != tt.expected[i] || v.Rule != RuleEmojiVersion {
+					t.Errorf("violation %d = %q (%s), want %q (%s)", i, v.Msg, v.Rule, tt.expected[i], RuleEmojiVersion)
+				}
+			}
+		})
+	}
+}
+
+// TestLintWorkflowReport_AllowDeny tests emoji.allow and emoji.deny entries of every form
+func TestLintWorkflowReport_AllowDeny(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_emoji.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	node, err := ParseYAML("testdata/valid_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	expected := []string{
+		`[Workflow] 🚀 Valid Workflow: Emoji 🚀 is denied by emoji.deny entry "🚀" (denied-emoji)`,
+		"[Step] 📥 Checkout code: Emoji 📥 is not in the allowed list (emoji.allow) (allowed-emoji)",
+		"[Step] 🐹 Setup Go: Emoji 🐹 is not in the allowed list (emoji.allow) (allowed-emoji)",
+		// 🏗️ is allowed by the Travel & Places group
+		"[Step] 📥 Checkout code: Emoji 📥 is not in the allowed list (emoji.allow) (allowed-emoji)",
+		`[Job] deploy: Emoji 🚀 is denied by emoji.deny entry "🚀" (denied-emoji)`,
+		"[Step] 📦 Deploy application: Emoji 📦 is not in the allowed list (emoji.allow) (allowed-emoji)",
+	}
+
+	if len(report.Violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %+v", len(expected), len(report.Violations), report.Violations)
+	}
+	for i, v := range report.Violations {
+		if got := fmt.Sprintf("[%s] %s: %s (%s)", v.Type, v.Identifier, v.Msg, v.Rule); got != expected[i] {
+			t.Errorf("violation %d = %q, want %q", i, got, expected[i])
+		}
+	}
+}
+
+// TestLintWorkflowReport_MaxEmoji tests that emoji are counted as whole grapheme clusters
+func TestLintWorkflowReport_MaxEmoji(t *testing.T) {
+	tests := []struct {
+		maxEmoji int
+		expected []string
+	}{
+		{0, nil},
+		{2, []string{"Name has 4 emoji, more than levels.workflow.maxEmoji 2"}},
+		{1, []string{
+			"Name has 4 emoji, more than levels.workflow.maxEmoji 1",
+			"Name has 2 emoji, more than levels.job.maxEmoji 1",
+			"Name has 2 emoji, more than levels.step.maxEmoji 1",
+			"Name has 2 emoji, more than levels.step.maxEmoji 1",
+		}},
+	}
+
+	node, err := ParseYAML("testdata/emoji_count.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.maxEmoji), func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Levels.Workflow.MaxEmoji = tt.maxEmoji
+			cfg.Levels.Job.MaxEmoji = tt.maxEmoji
+			cfg.Levels.Step.MaxEmoji = tt.maxEmoji
+
+			report, err := LintWorkflowReport(node, cfg)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			if len(report.Violations) != len(tt.expected) {
+				t.Fatalf("Expected %d violations, got %d: %+v", len(tt.expected), len(report.Violations), report.Violations)
+			}
+			for i, v := range report.Violations {
+				if v.Msg != tt.expected[i] || v.Rule != RuleEmojiCount {
+					t.Errorf("violation %d = %q (%s), want %q (%s)", i, v.Msg, v.Rule, tt.expected[i], RuleEmojiCount)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxLocations bounds the other locations listed in a consistency message.
+const maxLocations = 3
+
+// Consistency collects names across workflow files to check that steps
+// using the same action, and jobs with the same ID, carry the same emoji in
+// every workflow, that each emoji keeps one meaning, and that no two
+// workflows share a name. Unlike LintWorkflowReport, it needs every file
+// before it can report anything.
+type Consistency struct {
+	groups map[consistencyGroup][]consistencyRef
+	order  []consistencyGroup
+
+	workflows     map[string][]consistencyRef
+	workflowOrder []string
+
+	meanings     map[string][]meaningRef
+	meaningOrder []string
+}
+
+// consistencyGroup is a set of names that must share an emoji: the steps
+// using one action, or the jobs with one ID.
+type consistencyGroup struct {
+	Rule string
+	Key  string
+}
+
+// consistencyRef is a name of a consistency group and the emoji it carries.
+type consistencyRef struct {
+	File       string
+	Type       GithubActionType
+	Identifier string
+	Line       int
+	Column     int
+	Emoji      Emoji
+	cfg        *Config
+}
+
+// NewConsistency returns an empty collection.
+func NewConsistency() *Consistency {
+	return &Consistency{
+		groups:    map[consistencyGroup][]consistencyRef{},
+		workflows: map[string][]consistencyRef{},
+		meanings:  map[string][]meaningRef{},
+	}
+}
+
+// Add collects the names of a workflow file, linted with cfg. Names on
+// disabled levels and of ignored jobs and steps are left out, and so are
+// names without an emoji except for the workflow name.
+func (c *Consistency) Add(file string, root *yaml.Node, cfg *Config) {
+	for _, ref := range CollectNames(root) {
+		level := cfg.Level(ref.Type)
+		if !level.Enabled || !ref.Named {
+			continue
+		}
+		if _, ignored := cfg.Level(Job).IgnoredBy(ref.JobID); ignored && ref.Type != Workflow {
+			continue
+		}
+		if _, ignored := level.IgnoredBy(ref.Name); ignored && ref.Type == Step {
+			continue
+		}
+
+		if ref.Type == Workflow {
+			c.addWorkflow(file, ref, cfg)
+		}
+
+		emoji, ok := placedEmoji(ref.Name, level.Placement)
+		if !ok {
+			continue
+		}
+
+		named := consistencyRef{
+			File:       file,
+			Type:       ref.Type,
+			Identifier: ref.Name,
+			Line:       ref.Node.Line,
+			Column:     ref.Node.Column,
+			Emoji:      emoji,
+			cfg:        cfg,
+		}
+		if ref.Type == Job {
+			named.Identifier = ref.JobID
+		}
+		c.addMeaning(named, nameText(ref.Name, emojiSlots(ref.Name, level.Placement)))
+
+		switch {
+		case ref.Type == Job:
+			c.add(consistencyGroup{Rule: RuleJobEmoji, Key: ref.JobID}, named)
+		case ref.Type == Step && ref.Uses != "":
+			c.add(consistencyGroup{Rule: RuleActionEmoji, Key: actionName(ref.Uses)}, named)
+		}
+	}
+}
+
+func (c *Consistency) add(group consistencyGroup, ref consistencyRef) {
+	if _, ok := c.groups[group]; !ok {
+		c.order = append(c.order, group)
+	}
+	c.groups[group] = append(c.groups[group], ref)
+}
+
+// Violations returns, by file, the names whose emoji differs from the one
+// the config maps their group to or, when it maps none, from the emoji most
+// names of the group carry. Groups split evenly between emoji are left alone.
+func (c *Consistency) Violations() map[string][]Violation {
+	violations := map[string][]Violation{}
+
+	for _, group := range c.order {
+		refs := c.groups[group]
+		majority := majorityEmoji(refs)
+
+		for _, ref := range refs {
+			var msg string
+			if pattern, ok := ref.mapping(group); ok {
+				if pattern.matches(ref.Emoji) {
+					continue
+				}
+				msg = fmt.Sprintf("Emoji %s differs from %s, which %s sets for %s", ref.Emoji.Text, pattern.sequence, group.mappingKey(), group.Key)
+			} else {
+				if majority == "" || consistencyKey(ref.Emoji) == majority {
+					continue
+				}
+				others := refsWithEmoji(refs, majority)
+				msg = fmt.Sprintf("Emoji %s differs from %s used by %d other %s: %s",
+					ref.Emoji.Text, others[0].Emoji.Text, len(others), group.describe(), locations(others))
+			}
+
+			severity := ref.cfg.Severity(group.Rule)
+			if severity == SeverityOff {
+				continue
+			}
+			violations[ref.File] = append(violations[ref.File], Violation{
+				Type:       ref.Type,
+				Identifier: ref.Identifier,
+				Msg:        msg,
+				Rule:       group.Rule,
+				Severity:   severity,
+				Line:       ref.Line,
+				Column:     ref.Column,
+			})
+		}
+	}
+
+	c.meaningViolations(violations)
+	c.workflowNameViolations(violations)
+
+	for _, vs := range violations {
+		sort.SliceStable(vs, func(i, j int) bool { return vs[i].Line < vs[j].Line })
+	}
+
+	return violations
+}
+
+// mapping returns the emoji the config of a name maps its group to.
+func (r consistencyRef) mapping(group consistencyGroup) (emojiPattern, bool) {
+	mapping := r.cfg.Emoji.actions
+	if group.Rule == RuleJobEmoji {
+		mapping = r.cfg.Emoji.jobs
+	}
+
+	pattern, ok := mapping[strings.ToLower(group.Key)]
+	return pattern, ok
+}
+
+// mappingKey returns the config key that maps the groups of a rule to emoji.
+func (g consistencyGroup) mappingKey() string {
+	if g.Rule == RuleJobEmoji {
+		return "emoji.jobs"
+	}
+	return "emoji.actions"
+}
+
+// describe names the members of a group, such as "step(s) with actions/checkout".
+func (g consistencyGroup) describe() string {
+	if g.Rule == RuleJobEmoji {
+		return "job(s) with ID " + g.Key
+	}
+	return "step(s) with " + g.Key
+}
+
+// actionName returns the action of a `uses:` value without its version ref:
+// actions/checkout@v4 becomes actions/checkout.
+func actionName(uses string) string {
+	name, _, _ := strings.Cut(uses, "@")
+	return strings.ToLower(name)
+}
+
+// consistencyKey identifies an emoji regardless of its variation selectors
+// and skin tones, which do not make names inconsistent.
+func consistencyKey(e Emoji) string {
+	return stripVariationSelectors(stripSkinTones(e.Qualified))
+}
+
+// majorityEmoji returns the key of the emoji most names carry, or "" when
+// two emoji tie for the most names and neither can be told apart as the odd
+// one out.
+func majorityEmoji(refs []consistencyRef) string {
+	counts := map[string]int{}
+	for _, ref := range refs {
+		counts[consistencyKey(ref.Emoji)]++
+	}
+
+	majority, tied := "", false
+	for key, count := range counts {
+		switch {
+		case count > counts[majority]:
+			majority, tied = key, false
+		case count == counts[majority]:
+			tied = true
+		}
+	}
+	if tied {
+		return ""
+	}
+
+	return majority
+}
+
+// refsWithEmoji returns the names carrying the emoji with the given key.
+func refsWithEmoji(refs []consistencyRef, key string) []consistencyRef {
+	var matching []consistencyRef
+	for _, ref := range refs {
+		if consistencyKey(ref.Emoji) == key {
+			matching = append(matching, ref)
+		}
+	}
+
+	return matching
+}
+
+// locations lists where names were found as file:line, shortened to
+// maxLocations entries.
+func locations(refs []consistencyRef) string {
+	var listed []string
+	for i, ref := range refs {
+		if i == maxLocations {
+			listed = append(listed, fmt.Sprintf("and %d more", len(refs)-maxLocations))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s:%d", ref.File, ref.Line))
+	}
+
+	return strings.Join(listed, ", ")
+}
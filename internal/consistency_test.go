@@ -0,0 +1,125 @@
+package internal
+
+import "testing"
+
+// consistencyFiles are the workflows of TestConsistency, in the order they are added.
+var consistencyFiles = []string{
+	"testdata/consistency/ci.yml",
+	"testdata/consistency/release.yml",
+	"testdata/consistency/docs.yml",
+	"testdata/consistency/nightly.yml",
+}
+
+// TestConsistency tests that steps using the same action, and jobs with the same ID, are compared across files
+func TestConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   func(cfg *Config)
+		expected []string
+	}{
+		{
+			name: "majority",
+			expected: []string{
+				"testdata/consistency/ci.yml:10 [Step] 🟩 Setup Node: Emoji 🟩 differs from 🟢 used by 2 other step(s) with actions/setup-node: testdata/consistency/release.yml:10, testdata/consistency/docs.yml:10 (warning)",
+				"testdata/consistency/docs.yml:8 [Step] 🛒 Checkout: Emoji 🛒 differs from 📥 used by 2 other step(s) with actions/checkout: testdata/consistency/ci.yml:8, testdata/consistency/release.yml:8 (warning)",
+				"testdata/consistency/docs.yml:14 [Step] 🤝 Welcome: Emoji 🤝 differs from 🫱🏻‍🫲🏼 used by 2 other step(s) with actions/first-interaction: testdata/consistency/ci.yml:14, testdata/consistency/release.yml:12 (warning)",
+				"testdata/consistency/nightly.yml:5 [Job] build: Emoji 🔨 differs from 🏗️ used by 2 other job(s) with ID build: testdata/consistency/ci.yml:5, testdata/consistency/release.yml:5 (warning)",
+			},
+		},
+		{
+			name: "mapping",
+			config: func(cfg *Config) {
+				cfg.Emoji.Actions = map[string]string{"actions/checkout": ":shopping_cart:"}
+				cfg.Emoji.Jobs = map[string]string{"build": "🔨"}
+				cfg.Rules[RuleActionEmoji] = SeverityError
+			},
+			expected: []string{
+				"testdata/consistency/ci.yml:5 [Job] build: Emoji 🏗️ differs from 🔨, which emoji.jobs sets for build (warning)",
+				"testdata/consistency/ci.yml:8 [Step] 📥 Checkout: Emoji 📥 differs from 🛒, which emoji.actions sets for actions/checkout (error)",
+				"testdata/consistency/ci.yml:10 [Step] 🟩 Setup Node: Emoji 🟩 differs from 🟢 used by 2 other step(s) with actions/setup-node: testdata/consistency/release.yml:10, testdata/consistency/docs.yml:10 (error)",
+				"testdata/consistency/release.yml:5 [Job] build: Emoji 🏗️ differs from 🔨, which emoji.jobs sets for build (warning)",
+				"testdata/consistency/release.yml:8 [Step] 📥 Checkout: Emoji 📥 differs from 🛒, which emoji.actions sets for actions/checkout (error)",
+				"testdata/consistency/docs.yml:14 [Step] 🤝 Welcome: Emoji 🤝 differs from 🫱🏻‍🫲🏼 used by 2 other step(s) with actions/first-interaction: testdata/consistency/ci.yml:14, testdata/consistency/release.yml:12 (error)",
+			},
+		},
+		{
+			name: "ignored steps",
+			config: func(cfg *Config) {
+				cfg.Levels.Step.Ignore = []string{"Node$"}
+			},
+			expected: []string{
+				"testdata/consistency/docs.yml:8 [Step] 🛒 Checkout: Emoji 🛒 differs from 📥 used by 2 other step(s) with actions/checkout: testdata/consistency/ci.yml:8, testdata/consistency/release.yml:8 (warning)",
+				"testdata/consistency/docs.yml:14 [Step] 🤝 Welcome: Emoji 🤝 differs from 🫱🏻‍🫲🏼 used by 2 other step(s) with actions/first-interaction: testdata/consistency/ci.yml:14, testdata/consistency/release.yml:12 (warning)",
+				"testdata/consistency/nightly.yml:5 [Job] build: Emoji 🔨 differs from 🏗️ used by 2 other job(s) with ID build: testdata/consistency/ci.yml:5, testdata/consistency/release.yml:5 (warning)",
+			},
+		},
+		{
+			// The steps of ignored jobs are left out as well
+			name: "ignored jobs",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Ignore = []string{"^build$"}
+			},
+		},
+		{
+			name: "rule off",
+			config: func(cfg *Config) {
+				cfg.Rules[RuleActionEmoji] = SeverityOff
+				cfg.Rules[RuleJobEmoji] = SeverityOff
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			compileConfig(t, cfg)
+
+			consistency := NewConsistency()
+			for _, file := range consistencyFiles {
+				node, err := ParseYAML(file)
+				if err != nil {
+					t.Fatalf("Failed to parse workflow: %v", err)
+				}
+				consistency.Add(file, node, cfg)
+			}
+
+			assertCrossFileViolations(t, consistencyFiles, consistency.Violations(), "", tt.expected)
+		})
+	}
+}
+
+// TestMajorityEmoji tests that the majority ignores skin tones and is left
+// undecided on a tie, whatever the order of the names
+func TestMajorityEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		emoji    []string
+		expected string
+	}{
+		{"majority", []string{"🛒", "📥", "📥"}, "📥"},
+		{"skin tones", []string{"👍🏻", "👍🏽", "🙂"}, "👍"},
+		{"tie", []string{"🛒", "📥"}, ""},
+		{"tie reversed", []string{"📥", "🛒"}, ""},
+		{"tie below majority", []string{"🛒", "🟢", "📥", "📥"}, "📥"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var refs []consistencyRef
+			for _, text := range tt.emoji {
+				e, err := LookupEmoji(text)
+				if err != nil {
+					t.Fatalf("LookupEmoji(%q) failed: %v", text, err)
+				}
+				refs = append(refs, consistencyRef{Emoji: e})
+			}
+
+			if got := majorityEmoji(refs); got != tt.expected {
+				t.Errorf("majorityEmoji(%q) = %q, want %q", tt.emoji, got, tt.expected)
+			}
+		})
+	}
+}
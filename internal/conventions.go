@@ -0,0 +1,406 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxReportedEmoji    = 20
+	maxReportedKeywords = 10
+	minKeywordLength    = 3
+)
+
+// stopwords are left out of the keyword → emoji pairs because they carry no meaning.
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true, "using": true,
+}
+
+// Count is a value and how often it was seen.
+type Count struct {
+	Value string
+	Count int
+}
+
+// KeywordEmoji is a word from a name paired with the emoji the name starts with.
+type KeywordEmoji struct {
+	Keyword string
+	Emoji   string
+	Count   int
+}
+
+// Conventions summarizes how names are already written across a set of
+// workflows. It is used by `emojigate init` to propose a config.
+type Conventions struct {
+	Files          int
+	Names          int
+	WithEmoji      int
+	Steps          int
+	NamedSteps     int
+	StepsWithEmoji int
+	// Unnamed counts the workflows and jobs without a display name.
+	Unnamed    int
+	Emoji      []Count
+	Placements []Count
+	Separators []Count
+	Keywords   []KeywordEmoji
+	// Broken counts the violations of each rule under the proposed levels,
+	// for rules that would fail the build.
+	Broken map[string]int
+}
+
+// DetectConventions scans the names of the given workflows, then lints them
+// with the proposed levels to find the rules they already break.
+func DetectConventions(workflows []*yaml.Node) *Conventions {
+	conv := &Conventions{Files: len(workflows)}
+	emoji := map[string]int{}
+	placements := map[string]int{}
+	separators := map[string]int{}
+	keywords := map[KeywordEmoji]int{}
+
+	for _, root := range workflows {
+		for _, ref := range CollectNames(root) {
+			if ref.Type == Step {
+				conv.Steps++
+				if ref.Named {
+					conv.NamedSteps++
+				}
+			}
+			if !ref.Named {
+				if ref.Type != Step {
+					conv.Unnamed++
+				}
+				continue
+			}
+
+			conv.Names++
+			placement, ok := placementOf(ref.Name)
+			if !ok {
+				continue
+			}
+
+			conv.WithEmoji++
+			if ref.Type == Step {
+				conv.StepsWithEmoji++
+			}
+			placements[string(placement)]++
+
+			slot := emojiSlots(ref.Name, placement)[0]
+			emoji[slot.Emoji.Qualified]++
+			if separator, _, ok := slot.separator(ref.Name); ok {
+				separators[separator]++
+			}
+			for _, word := range keywordsOf(strings.ReplaceAll(ref.Name, slot.Emoji.Text, " ")) {
+				keywords[KeywordEmoji{Keyword: word, Emoji: slot.Emoji.Qualified}]++
+			}
+		}
+	}
+
+	conv.Emoji = sortedCounts(emoji)
+	conv.Placements = sortedCounts(placements)
+	conv.Separators = sortedCounts(separators)
+
+	for pair, count := range keywords {
+		if count < 2 {
+			continue
+		}
+		pair.Count = count
+		conv.Keywords = append(conv.Keywords, pair)
+	}
+	sort.Slice(conv.Keywords, func(i, j int) bool {
+		a, b := conv.Keywords[i], conv.Keywords[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Keyword != b.Keyword {
+			return a.Keyword < b.Keyword
+		}
+		return a.Emoji < b.Emoji
+	})
+
+	conv.Broken = conv.brokenRules(workflows)
+	return conv
+}
+
+// placementOf returns where a name carries its emoji: at both ends, at the
+// start, at the end or elsewhere.
+func placementOf(name string) (Placement, bool) {
+	leading, trailing := leadingSlot(name).ok, trailingSlot(name).ok
+	switch {
+	case leading && trailing && countEmoji(name) > 1:
+		return PlacementWrap, true
+	case leading:
+		return PlacementPrefix, true
+	case trailing:
+		return PlacementSuffix, true
+	case countEmoji(name) > 0:
+		return PlacementAnywhere, true
+	}
+
+	return "", false
+}
+
+// brokenRules lints the workflows with the proposed levels and counts the
+// violations of each rule that would fail the build.
+func (c *Conventions) brokenRules(workflows []*yaml.Node) map[string]int {
+	cfg := DefaultConfig()
+	for _, level := range []*Level{&cfg.Levels.Workflow, &cfg.Levels.Job, &cfg.Levels.Step} {
+		level.Placement = c.placement()
+		level.Separator = c.separator()
+	}
+	cfg.Levels.Step.Enabled = c.stepsLinted()
+
+	broken := map[string]int{}
+	count := func(violations []Violation) {
+		for _, v := range violations {
+			if v.Severity == SeverityError {
+				broken[v.Rule]++
+			}
+		}
+	}
+
+	consistency := NewConsistency()
+	for i, root := range workflows {
+		report, err := LintWorkflowReport(root, cfg)
+		if err != nil {
+			continue
+		}
+		count(report.Violations)
+		consistency.Add(strconv.Itoa(i), root, cfg)
+	}
+	for _, violations := range consistency.Violations() {
+		count(violations)
+	}
+
+	return broken
+}
+
+// placement proposes the placement most names with an emoji follow.
+func (c *Conventions) placement() Placement {
+	return Placement(mostCommon(c.Placements, string(PlacementPrefix)).Value)
+}
+
+// separator proposes the separator most names with an emoji use.
+func (c *Conventions) separator() string {
+	return mostCommon(c.Separators, DefaultSeparator).Value
+}
+
+// mostCommon returns the first of counts, sorted by count, unless fallback
+// is seen as often. Without counts, fallback is returned with a zero count.
+func mostCommon(counts []Count, fallback string) Count {
+	for _, count := range counts {
+		if count.Value == fallback && count.Count == counts[0].Count {
+			return count
+		}
+	}
+	if len(counts) == 0 {
+		return Count{Value: fallback}
+	}
+
+	return counts[0]
+}
+
+// stepsLinted proposes to lint step names only when every step is named, as
+// linting fails on unnamed steps, and most of them already carry an emoji.
+func (c *Conventions) stepsLinted() bool {
+	return c.NamedSteps == c.Steps && c.StepsWithEmoji*2 >= c.NamedSteps
+}
+
+func keywordsOf(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len(word) < minKeywordLength || stopwords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words
+}
+
+func sortedCounts(counts map[string]int) []Count {
+	result := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, Count{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+
+	return result
+}
+
+func describeSeparator(separator string) string {
+	switch separator {
+	case "":
+		return "none"
+	case " ":
+		return "single space"
+	default:
+		return fmt.Sprintf("%q", separator)
+	}
+}
+
+// RenderConfig returns a commented .emojigate.yml reflecting the detected conventions.
+func (c *Conventions) RenderConfig() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# yaml-language-server: $schema=%s\n", SchemaURL)
+	fmt.Fprintln(&b, "#")
+	fmt.Fprintln(&b, "# emojigate configuration")
+	fmt.Fprintf(&b, "# Generated by `emojigate init` from %d workflow file(s).\n", c.Files)
+	fmt.Fprintln(&b, "#")
+	fmt.Fprintln(&b, "# Conventions detected:")
+	fmt.Fprintf(&b, "#   Names with an emoji: %d of %d\n", c.WithEmoji, c.Names)
+	fmt.Fprintf(&b, "#   Named steps: %d of %d (%d with an emoji)\n", c.NamedSteps, c.Steps, c.StepsWithEmoji)
+	if c.Unnamed > 0 {
+		fmt.Fprintf(&b, "#   Workflows and jobs without a name: %d\n", c.Unnamed)
+	}
+
+	if len(c.Emoji) > 0 {
+		var in []string
+		for i, e := range c.Emoji {
+			if i == maxReportedEmoji {
+				in = append(in, fmt.Sprintf("and %d more", len(c.Emoji)-maxReportedEmoji))
+				break
+			}
+			in = append(in, fmt.Sprintf("%s (%d)", e.Value, e.Count))
+		}
+		fmt.Fprintf(&b, "#   Emoji in use: %s\n", strings.Join(in, ", "))
+	}
+
+	if len(c.Placements) > 0 {
+		top := mostCommon(c.Placements, string(PlacementPrefix))
+		fmt.Fprintf(&b, "#   Emoji placement: %s (%d of %d names)\n", top.Value, top.Count, c.WithEmoji)
+	}
+
+	if len(c.Separators) > 0 {
+		top := mostCommon(c.Separators, DefaultSeparator)
+		fmt.Fprintf(&b, "#   Separator after the emoji: %s (%d of %d names)\n", describeSeparator(top.Value), top.Count, c.WithEmoji)
+	}
+
+	if len(c.Keywords) > 0 {
+		fmt.Fprintln(&b, "#   Frequent keyword → emoji pairs:")
+		for i, k := range c.Keywords {
+			if i == maxReportedKeywords {
+				break
+			}
+			fmt.Fprintf(&b, "#     %s → %s (%d)\n", k.Keyword, k.Emoji, k.Count)
+		}
+	}
+
+	var broken []string
+	for _, rule := range Rules {
+		if count := c.Broken[rule.ID]; count > 0 {
+			broken = append(broken, fmt.Sprintf("%s (%d)", rule.ID, count))
+		}
+	}
+	if len(broken) > 0 {
+		fmt.Fprintf(&b, "#   Rules existing names break, starting as warnings: %s\n", strings.Join(broken, ", "))
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "# Presets (%s) or shared config files to build on.\n", strings.Join(Presets(), ", "))
+	fmt.Fprintln(&b, "# Settings below take precedence; lists are appended to.")
+	fmt.Fprintln(&b, "# extends: [relaxed]")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "# Workflow files to skip, as glob patterns.")
+	fmt.Fprintln(&b, "ignore: []")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "# Whether names are linted, where they carry their emoji and the text between")
+	fmt.Fprintln(&b, "# the emoji and the rest of the name. Settings left commented out keep their")
+	fmt.Fprintln(&b, "# default.")
+	levels := []yamlLine{{Text: "levels:"}}
+	for _, t := range []GithubActionType{Workflow, Job, Step} {
+		level := yamlLine{Depth: 1, Text: strings.ToLower(string(t)) + ":"}
+		var settings []yamlLine
+		for _, setting := range c.levelSettings(t) {
+			settings = append(settings, yamlLine{Depth: 2, Text: setting.Line, Set: setting.Changed})
+			level.Set = level.Set || setting.Changed
+		}
+		levels[0].Set = levels[0].Set || level.Set
+		levels = append(append(levels, level), settings...)
+	}
+	renderLines(&b, levels)
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "# Rule severities: error, warning or off. Rules left commented out keep their")
+	fmt.Fprintln(&b, "# default severity, or the one of the presets extended above.")
+	rules := []yamlLine{{Text: "rules:"}}
+	for _, rule := range Rules {
+		severity := c.severity(rule)
+		rules = append(rules, yamlLine{Depth: 1, Text: fmt.Sprintf("%s: %s # %s", rule.ID, severity, rule.Description), Set: severity != rule.Default})
+		rules[0].Set = rules[0].Set || severity != rule.Default
+	}
+	renderLines(&b, rules)
+
+	return b.String()
+}
+
+// levelSetting is a proposed setting of a level as a YAML line, and whether
+// it differs from the default.
+type levelSetting struct {
+	Line    string
+	Changed bool
+}
+
+// levelSettings proposes the settings of names of type t.
+func (c *Conventions) levelSettings(t GithubActionType) []levelSetting {
+	var settings []levelSetting
+	if t == Step {
+		settings = append(settings, levelSetting{fmt.Sprintf("enabled: %t", c.stepsLinted()), !c.stepsLinted()})
+	}
+
+	return append(settings,
+		levelSetting{fmt.Sprintf("placement: %s", c.placement()), c.placement() != PlacementPrefix},
+		levelSetting{fmt.Sprintf("separator: %s", strconv.Quote(c.separator())), c.separator() != DefaultSeparator},
+	)
+}
+
+// yamlLine is a line of a YAML block at an indentation depth, commented out
+// unless set. Mappings are set when any of their entries is, as an empty
+// mapping is invalid.
+type yamlLine struct {
+	Depth int
+	Text  string
+	Set   bool
+}
+
+// renderLines writes lines, starting each comment at the column of the
+// outermost line commented out, so that uncommenting a block keeps its
+// indentation.
+func renderLines(b *strings.Builder, lines []yamlLine) {
+	commented := -1
+	for _, line := range lines {
+		if line.Depth <= commented {
+			commented = -1
+		}
+		if commented < 0 && !line.Set {
+			commented = line.Depth
+		}
+
+		indent := strings.Repeat("  ", line.Depth)
+		if commented >= 0 {
+			indent = strings.Repeat("  ", commented) + "# " + strings.Repeat("  ", line.Depth-commented)
+		}
+		fmt.Fprintln(b, indent+line.Text)
+	}
+}
+
+// severity proposes a rule severity. Rules that the workflows already break
+// start as warnings, so adopting emojigate does not break the build; the
+// others keep their default.
+func (c *Conventions) severity(rule Rule) Severity {
+	if c.Broken[rule.ID] > 0 {
+		return SeverityWarning
+	}
+
+	return rule.Default
+}
@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseWorkflows(t *testing.T, files ...string) []*yaml.Node {
+	t.Helper()
+
+	var workflows []*yaml.Node
+	for _, file := range files {
+		node, err := ParseYAML(file)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		workflows = append(workflows, node)
+	}
+
+	return workflows
+}
+
+// TestDetectConventions tests the conventions inferred from existing workflows
+func TestDetectConventions(t *testing.T) {
+	conv := DetectConventions(parseWorkflows(t, "testdata/valid_workflow.yml", "testdata/invalid_workflow.yml"))
+
+	if conv.Files != 2 {
+		t.Errorf("Files = %d, want 2", conv.Files)
+	}
+	if conv.Names != 18 || conv.WithEmoji != 11 {
+		t.Errorf("Names = %d, WithEmoji = %d, want 18 and 11", conv.Names, conv.WithEmoji)
+	}
+	if conv.Steps != 11 || conv.NamedSteps != 11 || conv.StepsWithEmoji != 7 {
+		t.Errorf("Steps = %d, NamedSteps = %d, StepsWithEmoji = %d, want 11, 11 and 7", conv.Steps, conv.NamedSteps, conv.StepsWithEmoji)
+	}
+	if len(conv.Placements) != 1 || conv.Placements[0] != (Count{Value: string(PlacementPrefix), Count: 11}) {
+		t.Errorf("Placements = %+v, want prefix only", conv.Placements)
+	}
+	if conv.Broken[RuleRequireEmoji] != 7 {
+		t.Errorf("Broken = %v, want 7 require-emoji violations", conv.Broken)
+	}
+
+	// Ties are ordered by emoji, so the most frequent ones come first
+	if len(conv.Emoji) != 8 || conv.Emoji[0] != (Count{Value: "🏗️", Count: 2}) || conv.Emoji[7].Count != 1 {
+		t.Errorf("Emoji = %+v, want 8 emoji with 🏗️ first", conv.Emoji)
+	}
+	if len(conv.Separators) != 1 || conv.Separators[0].Value != " " {
+		t.Errorf("Separators = %+v, want a single space only", conv.Separators)
+	}
+
+	found := false
+	for _, k := range conv.Keywords {
+		if k.Keyword == "build" && k.Emoji == "🏗️" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Keywords = %+v, want build → 🏗️", conv.Keywords)
+	}
+}
+
+// TestConventions_RenderConfig tests that the rendered config loads and reflects the conventions
+func TestConventions_RenderConfig(t *testing.T) {
+	tests := []struct {
+		name                string
+		files               []string
+		expectedRequireRule Severity
+	}{
+		{"mostly compliant", []string{"testdata/valid_workflow.yml"}, SeverityError},
+		{"mostly non-compliant", []string{"testdata/invalid_workflow.yml"}, SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := DetectConventions(parseWorkflows(t, tt.files...)).RenderConfig()
+
+			problems, err := ValidateConfig([]byte(rendered))
+			if err != nil || len(problems) > 0 {
+				t.Fatalf("Rendered config is invalid: %v %v\n%s", err, problems, rendered)
+			}
+
+			cfg := DefaultConfig()
+			if err := yaml.Unmarshal([]byte(rendered), cfg); err != nil {
+				t.Fatalf("Rendered config is not valid YAML: %v\n%s", err, rendered)
+			}
+			if got := cfg.Severity(RuleRequireEmoji); got != tt.expectedRequireRule {
+				t.Errorf("Severity(%s) = %s, want %s", RuleRequireEmoji, got, tt.expectedRequireRule)
+			}
+			if !strings.Contains(rendered, "# Conventions detected:") {
+				t.Errorf("Rendered config has no conventions comment:\n%s", rendered)
+			}
+		})
+	}
+}
+
+// TestConventions_RenderConfigExtends tests that uncommenting the extends hint applies the preset
+func TestConventions_RenderConfigExtends(t *testing.T) {
+	tests := []struct {
+		name                string
+		files               []string
+		expectedRequireRule Severity
+	}{
+		// The preset relaxes require-emoji as the config leaves it at its default
+		{"mostly compliant", []string{"testdata/valid_workflow.yml"}, SeverityWarning},
+		{"mostly non-compliant", []string{"testdata/invalid_workflow.yml"}, SeverityWarning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := DetectConventions(parseWorkflows(t, tt.files...)).RenderConfig()
+			if !strings.Contains(rendered, "\n# extends: [relaxed]\n") {
+				t.Fatalf("Rendered config has no extends hint:\n%s", rendered)
+			}
+			cfg := loadRenderedConfig(t, strings.Replace(rendered, "\n# extends: [relaxed]\n", "\nextends: [relaxed]\n", 1))
+
+			if got := cfg.Severity(RuleRequireEmoji); got != tt.expectedRequireRule {
+				t.Errorf("Severity(%s) = %s, want %s", RuleRequireEmoji, got, tt.expectedRequireRule)
+			}
+			if got := cfg.Severity(RuleSeparator); got != SeverityWarning {
+				t.Errorf("Severity(%s) = %s, want %s from the preset", RuleSeparator, got, SeverityWarning)
+			}
+		})
+	}
+}
+
+// TestConventions_ProposedLevels tests the level settings and severities derived from the scan
+func TestConventions_ProposedLevels(t *testing.T) {
+	tests := []struct {
+		name             string
+		files            []string
+		stepsEnabled     bool
+		placement        Placement
+		separator        string
+		missingNameRule  Severity
+		requireEmojiRule Severity
+		separatorRule    Severity
+	}{
+		{"compliant", []string{"testdata/valid_workflow.yml"}, true, PlacementPrefix, " ", SeverityError, SeverityError, SeverityError},
+		// Most named steps carry no emoji, so steps are not linted
+		{"steps without emoji", []string{"testdata/invalid_workflow.yml"}, false, PlacementPrefix, " ", SeverityError, SeverityWarning, SeverityError},
+		// Separators tied with the default one keep it
+		{"mixed separators", []string{"testdata/separator_workflow.yml"}, true, PlacementPrefix, " ", SeverityError, SeverityError, SeverityWarning},
+		{"suffix", []string{"testdata/conventions/suffix.yml"}, false, PlacementSuffix, " - ", SeverityWarning, SeverityError, SeverityError},
+		{"missing job name", []string{"testdata/missing_job_name.yml"}, true, PlacementPrefix, " ", SeverityWarning, SeverityError, SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadRenderedConfig(t, DetectConventions(parseWorkflows(t, tt.files...)).RenderConfig())
+
+			if cfg.Levels.Step.Enabled != tt.stepsEnabled {
+				t.Errorf("levels.step.enabled = %t, want %t", cfg.Levels.Step.Enabled, tt.stepsEnabled)
+			}
+			for _, level := range []Level{cfg.Levels.Workflow, cfg.Levels.Job, cfg.Levels.Step} {
+				if level.Placement != tt.placement || level.Separator != tt.separator {
+					t.Errorf("placement = %s, separator = %q, want %s and %q", level.Placement, level.Separator, tt.placement, tt.separator)
+				}
+			}
+			for rule, expected := range map[string]Severity{RuleMissingName: tt.missingNameRule, RuleRequireEmoji: tt.requireEmojiRule, RuleSeparator: tt.separatorRule} {
+				if got := cfg.Severity(rule); got != expected {
+					t.Errorf("Severity(%s) = %s, want %s", rule, got, expected)
+				}
+			}
+		})
+	}
+}
+
+// TestConventions_InitThenLint tests that the workflows a config was generated from pass linting with it
+func TestConventions_InitThenLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+	}{
+		{"compliant and non-compliant", []string{"testdata/valid_workflow.yml", "testdata/invalid_workflow.yml"}},
+		{"separators", []string{"testdata/separator_workflow.yml"}},
+		{"suffix", []string{"testdata/conventions/suffix.yml"}},
+		// Most steps are named and carry an emoji, but unnamed steps cannot be linted
+		{"unnamed steps", []string{"testdata/conventions/suffix.yml", "testdata/valid_workflow.yml", "testdata/mixed_emojis.yml"}},
+		{"missing names", []string{"testdata/missing_job_name.yml", "testdata/missing_workflow_name.yml"}},
+		{"shortcodes and invisible characters", []string{"testdata/shortcode_workflow.yml", "testdata/invisible_workflow.yml", "testdata/text_presentation_workflow.yml"}},
+		{"consistency", []string{"testdata/consistency/ci.yml", "testdata/consistency/docs.yml", "testdata/consistency/nightly.yml", "testdata/consistency/release.yml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflows := parseWorkflows(t, tt.files...)
+			cfg := loadRenderedConfig(t, DetectConventions(workflows).RenderConfig())
+
+			consistency := NewConsistency()
+			var violations []Violation
+			for i, root := range workflows {
+				report, err := LintWorkflowReport(root, cfg)
+				if err != nil {
+					t.Fatalf("Failed to lint %s: %v", tt.files[i], err)
+				}
+				violations = append(violations, report.Violations...)
+				consistency.Add(tt.files[i], root, cfg)
+			}
+			for _, file := range tt.files {
+				violations = append(violations, consistency.Violations()[file]...)
+			}
+
+			for _, v := range violations {
+				if v.Severity == SeverityError {
+					t.Errorf("Lint fails with the generated config: [%s] %s: %s (%s)", v.Type, v.Identifier, v.Msg, v.Rule)
+				}
+			}
+		})
+	}
+}
+
+// loadRenderedConfig validates and loads a config rendered by `emojigate init`.
+func loadRenderedConfig(t *testing.T, rendered string) *Config {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(file, []byte(rendered), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(file)
+	if err != nil {
+		t.Fatalf("Failed to load rendered config: %v\n%s", err, rendered)
+	}
+
+	return cfg
+}
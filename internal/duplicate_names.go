@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// nameScope is a set of names that must be unique: the jobs of a workflow, or
+// the steps of one job.
+type nameScope struct {
+	Type  GithubActionType
+	JobID string
+	Name  string
+}
+
+// duplicateNames finds the jobs sharing a name with another job of the
+// workflow, and the steps sharing a name with another step of their job, and
+// maps their name nodes to a message listing the other locations. Names on
+// disabled levels and of ignored jobs and steps are left out, as the linter
+// skips them.
+func duplicateNames(root *yaml.Node, cfg *Config) map[*yaml.Node]string {
+	groups := map[nameScope][]NameRef{}
+	for _, ref := range CollectNames(root) {
+		if ref.Type == Workflow || !ref.Named || strings.TrimSpace(ref.Name) == "" || !cfg.Level(ref.Type).Enabled {
+			continue
+		}
+		if _, ignored := cfg.Level(Job).IgnoredBy(ref.JobID); ignored {
+			continue
+		}
+		if _, ignored := cfg.Level(Step).IgnoredBy(ref.Name); ignored && ref.Type == Step {
+			continue
+		}
+
+		scope := nameScope{Type: ref.Type, Name: ref.Name}
+		if ref.Type == Step {
+			scope.JobID = ref.JobID
+		}
+		groups[scope] = append(groups[scope], ref)
+	}
+
+	messages := map[*yaml.Node]string{}
+	for scope, refs := range groups {
+		if len(refs) < 2 {
+			continue
+		}
+
+		for _, ref := range refs {
+			var others []string
+			var lines []int
+			for _, other := range refs {
+				if other.Node == ref.Node {
+					continue
+				}
+				others = append(others, fmt.Sprintf("%s at line %d", other.JobID, other.Node.Line))
+				lines = append(lines, other.Node.Line)
+			}
+
+			if scope.Type == Step {
+				messages[ref.Node] = fmt.Sprintf("Name is also used by %d other step(s) of this job (%s)", len(lines), describeLines(lines))
+			} else {
+				messages[ref.Node] = fmt.Sprintf("Name is also used by %d other job(s) of this workflow (%s)", len(others), strings.Join(others, ", "))
+			}
+		}
+	}
+
+	return messages
+}
+
+func (c *Consistency) addWorkflow(file string, ref NameRef, cfg *Config) {
+	if !ref.Named || strings.TrimSpace(ref.Name) == "" {
+		return
+	}
+
+	if _, ok := c.workflows[ref.Name]; !ok {
+		c.workflowOrder = append(c.workflowOrder, ref.Name)
+	}
+	c.workflows[ref.Name] = append(c.workflows[ref.Name], consistencyRef{
+		File:       file,
+		Type:       Workflow,
+		Identifier: ref.Name,
+		Line:       ref.Node.Line,
+		Column:     ref.Node.Column,
+		cfg:        cfg,
+	})
+}
+
+// workflowNameViolations adds a violation for each workflow sharing its name
+// with another workflow, listing every other file using the name.
+func (c *Consistency) workflowNameViolations(violations map[string][]Violation) {
+	for _, name := range c.workflowOrder {
+		refs := c.workflows[name]
+		if len(refs) < 2 {
+			continue
+		}
+
+		for i, ref := range refs {
+			severity := ref.cfg.Severity(RuleDuplicateName)
+			if severity == SeverityOff {
+				continue
+			}
+
+			var others []string
+			for j, other := range refs {
+				if j != i {
+					others = append(others, fmt.Sprintf("%s:%d", other.File, other.Line))
+				}
+			}
+			violations[ref.File] = append(violations[ref.File], Violation{
+				Type:       Workflow,
+				Identifier: ref.Identifier,
+				Msg:        fmt.Sprintf("Name is also used by %d other workflow(s) (%s)", len(others), strings.Join(others, ", ")),
+				Rule:       RuleDuplicateName,
+				Severity:   severity,
+				Line:       ref.Line,
+				Column:     ref.Column,
+			})
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import "testing"
+
+// TestLintWorkflowReport_DuplicateName tests that jobs of a workflow and steps of a job must not share a name
+func TestLintWorkflowReport_DuplicateName(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   func(cfg *Config)
+		expected []string
+	}{
+		{
+			name: "default",
+			expected: []string{
+				"[Job] test-linux: Name is also used by 2 other job(s) of this workflow (test-macos at line 15, test-windows at line 21) (warning)",
+				"[Step] 🧪 Run tests: Name is also used by 1 other step(s) of this job (line 12) (warning)",
+				"[Step] 🧪 Run tests: Name is also used by 1 other step(s) of this job (line 10) (warning)",
+				"[Job] test-macos: Name is also used by 2 other job(s) of this workflow (test-linux at line 5, test-windows at line 21) (warning)",
+				"[Job] test-windows: Name is also used by 2 other job(s) of this workflow (test-linux at line 5, test-macos at line 15) (warning)",
+			},
+		},
+		{
+			// Ignored jobs are not linted, so they cannot collide
+			name: "ignored job",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Ignore = []string{"^test-(linux|windows)$"}
+			},
+		},
+		{
+			// Steps are still compared without job names
+			name: "job level disabled",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Enabled = false
+			},
+			expected: []string{
+				"[Step] 🧪 Run tests: Name is also used by 1 other step(s) of this job (line 12) (warning)",
+				"[Step] 🧪 Run tests: Name is also used by 1 other step(s) of this job (line 10) (warning)",
+			},
+		},
+		{
+			name: "rule off",
+			config: func(cfg *Config) {
+				cfg.Rules[RuleDuplicateName] = SeverityOff
+			},
+		},
+	}
+
+	node, err := ParseYAML("testdata/duplicate_names_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			compileConfig(t, cfg)
+
+			report, err := LintWorkflowReport(node, cfg)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			assertRuleViolations(t, report.Violations, RuleDuplicateName, tt.expected)
+		})
+	}
+}
+
+// TestConsistency_DuplicateWorkflowName tests that workflow names are compared across files
+func TestConsistency_DuplicateWorkflowName(t *testing.T) {
+	files := []string{
+		"testdata/duplicate_names_workflow.yml",
+		"testdata/duplicate_names_copy.yml",
+		"testdata/consistency/ci.yml",
+	}
+
+	consistency := NewConsistency()
+	for _, file := range files {
+		node, err := ParseYAML(file)
+		if err != nil {
+			t.Fatalf("Failed to parse workflow: %v", err)
+		}
+		consistency.Add(file, node, DefaultConfig())
+	}
+
+	expected := []string{
+		"testdata/duplicate_names_workflow.yml:1 [Workflow] 🚀 CI: Name is also used by 2 other workflow(s) (testdata/duplicate_names_copy.yml:1, testdata/consistency/ci.yml:1) (warning)",
+		"testdata/duplicate_names_copy.yml:1 [Workflow] 🚀 CI: Name is also used by 2 other workflow(s) (testdata/duplicate_names_workflow.yml:1, testdata/consistency/ci.yml:1) (warning)",
+		"testdata/consistency/ci.yml:1 [Workflow] 🚀 CI: Name is also used by 2 other workflow(s) (testdata/duplicate_names_workflow.yml:1, testdata/duplicate_names_copy.yml:1) (warning)",
+	}
+
+	assertCrossFileViolations(t, files, consistency.Violations(), RuleDuplicateName, expected)
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// siblingScopes describes the names a job or step name is compared with.
+var siblingScopes = map[GithubActionType]string{
+	Job:  "jobs of this workflow",
+	Step: "steps of this job",
+}
+
+// checkRepeat records the emoji of a job or step name among its siblings and
+// returns a message once more of them carry it than levels.*.maxRepeat
+// allows. Skin tones and variation selectors do not make emoji differ.
+func (l *linter) checkRepeat(t GithubActionType, level Level, e Emoji, line int) (string, bool) {
+	siblings, ok := l.siblings[t]
+	if !ok || level.MaxRepeat == 0 || level.AllowsRepeat(e) {
+		return "", false
+	}
+
+	key := consistencyKey(e)
+	earlier := siblings[key]
+	siblings[key] = append(earlier, line)
+	if len(earlier) < level.MaxRepeat {
+		return "", false
+	}
+
+	return fmt.Sprintf("Emoji %s is used by %d %s, more than levels.%s.maxRepeat %d (also at %s)",
+		e.Text, len(earlier)+1, siblingScopes[t], strings.ToLower(string(t)), level.MaxRepeat, describeLines(earlier)), true
+}
+
+// describeLines lists line numbers for messages: "line 8" or "lines 8, 10".
+func describeLines(lines []int) string {
+	numbers := make([]string, len(lines))
+	for i, line := range lines {
+		numbers[i] = strconv.Itoa(line)
+	}
+
+	if len(lines) == 1 {
+		return "line " + numbers[0]
+	}
+	return "lines " + strings.Join(numbers, ", ")
+}
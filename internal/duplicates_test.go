@@ -0,0 +1,37 @@
+package internal
+
+import "testing"
+
+// TestLintWorkflowReport_DuplicateEmoji tests that sibling jobs and steps may repeat an emoji up to levels.*.maxRepeat
+func TestLintWorkflowReport_DuplicateEmoji(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_duplicates.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	node, err := ParseYAML("testdata/duplicates_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	expected := []string{
+		// ⚙ without U+FE0F is the same emoji
+		"[Step] ⚙ Lint: Emoji ⚙ is used by 2 steps of this job, more than levels.step.maxRepeat 1 (also at line 12) (warning)",
+		"[Step] ⚙️ Vet: Emoji ⚙️ is used by 3 steps of this job, more than levels.step.maxRepeat 1 (also at lines 12, 14) (warning)",
+		"[Job] build: Emoji ⚙️ is used by 3 jobs of this workflow, more than levels.job.maxRepeat 2 (also at lines 5, 19) (warning)",
+	}
+
+	assertRuleViolations(t, report.Violations, RuleDuplicateEmoji, expected)
+
+	// The rule is off by default
+	report, err = LintWorkflowReport(node, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+	assertRuleViolations(t, report.Violations, RuleDuplicateEmoji, nil)
+}
@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//go:generate go run emoji_gen.go
+
+// Emoji is an RGI emoji sequence found at the start of a name.
+type Emoji struct {
+	// Text is the sequence as written in the name.
+	Text string
+	// Qualified is the fully-qualified form of the sequence. It differs from
+	// Text when variation selectors are missing or superfluous.
+	Qualified string
+	// Version is the Emoji version that introduced the sequence, such as "13.1".
+	Version string
+	// Name is the CLDR short name, such as "rocket".
+	Name string
+	// Group and Subgroup classify the emoji, such as "Travel & Places" and
+	// "transport-air".
+	Group    string
+	Subgroup string
+}
+
+// emojiSequence is a fully-qualified RGI emoji sequence from the Unicode data.
+type emojiSequence struct {
+	Text     string
+	Version  string
+	Name     string
+	Group    string
+	Subgroup string
+}
+
+// rgiByKey maps every RGI emoji sequence, without variation selectors, to its
+// fully-qualified form.
+var rgiByKey = func() map[string]emojiSequence {
+	m := make(map[string]emojiSequence, len(rgiEmoji))
+	for _, sequence := range rgiEmoji {
+		m[stripVariationSelectors(sequence.Text)] = sequence
+	}
+	return m
+}()
+
+// LeadingEmoji returns the emoji a name starts with. The first extended
+// grapheme cluster of name must be a complete RGI emoji sequence: a single
+// emoji, a keycap, a flag, a tag sequence, a modifier sequence or a ZWJ
+// sequence. Fully-qualified, minimally-qualified and unqualified forms are
+// accepted; components such as a lone skin tone or ZWJ are not.
+func LeadingEmoji(name string) (Emoji, bool) {
+	cluster := firstGrapheme(name)
+	if cluster == "" {
+		return Emoji{}, false
+	}
+
+	sequence, ok := rgiByKey[stripVariationSelectors(cluster)]
+	if !ok {
+		return Emoji{}, false
+	}
+
+	return Emoji{
+		Text:      cluster,
+		Qualified: sequence.Text,
+		Version:   sequence.Version,
+		Name:      sequence.Name,
+		Group:     sequence.Group,
+		Subgroup:  sequence.Subgroup,
+	}, true
+}
+
+// needsPresentationSelector reports whether an emoji is written without a
+// U+FE0F its fully-qualified form has. Characters with text presentation by
+// default, such as ✈ (U+2708), then render as monochrome glyphs.
+func needsPresentationSelector(e Emoji) bool {
+	return strings.Count(e.Qualified, "\uFE0F") > strings.Count(e.Text, "\uFE0F")
+}
+
+// LeadingShortcode returns the GitHub :shortcode: a name starts with, such as
+// ":rocket:", and the emoji it stands for. GitHub renders shortcodes in
+// Markdown but not in the names shown by Actions.
+func LeadingShortcode(name string) (shortcode, emoji string, ok bool) {
+	rest, found := strings.CutPrefix(name, ":")
+	if !found {
+		return "", "", false
+	}
+
+	alias, _, found := strings.Cut(rest, ":")
+	if !found {
+		return "", "", false
+	}
+
+	emoji, ok = emojiShortcodes[alias]
+	if !ok {
+		return "", "", false
+	}
+
+	return ":" + alias + ":", emoji, true
+}
+
+// TrailingShortcode returns the GitHub :shortcode: a name ends with and the
+// emoji it stands for.
+func TrailingShortcode(name string) (shortcode, emoji string, ok bool) {
+	rest, found := strings.CutSuffix(name, ":")
+	if !found {
+		return "", "", false
+	}
+
+	start := strings.LastIndex(rest, ":")
+	if start == -1 {
+		return "", "", false
+	}
+
+	return LeadingShortcode(name[start:])
+}
+
+// parseEmojiVersion parses an Emoji version such as "13.1" or "13".
+func parseEmojiVersion(version string) (major, minor int, err error) {
+	majorText, minorText, hasMinor := strings.Cut(version, ".")
+	if major, err = strconv.Atoi(majorText); err != nil || major < 0 {
+		return 0, 0, fmt.Errorf("invalid emoji version %q", version)
+	}
+	if hasMinor {
+		if minor, err = strconv.Atoi(minorText); err != nil || minor < 0 {
+			return 0, 0, fmt.Errorf("invalid emoji version %q", version)
+		}
+	}
+
+	return major, minor, nil
+}
+
+// newerEmojiVersion reports whether Emoji version a is newer than b. Both
+// must be valid versions.
+func newerEmojiVersion(a, b string) bool {
+	aMajor, aMinor, _ := parseEmojiVersion(a)
+	bMajor, bMinor, _ := parseEmojiVersion(b)
+	if aMajor != bMajor {
+		return aMajor > bMajor
+	}
+
+	return aMinor > bMinor
+}
+
+// stripVariationSelectors removes VS16, so that unqualified, minimally
+// qualified and fully-qualified forms of an emoji compare equal.
+func stripVariationSelectors(s string) string {
+	return strings.ReplaceAll(s, "\uFE0F", "")
+}
+
+// emojiPattern is an entry of emoji.allow or emoji.deny. It matches either a
+// single emoji sequence or every emoji of a group or subgroup.
+type emojiPattern struct {
+	sequence string
+	group    string
+}
+
+// parseEmojiPattern parses an emoji.allow or emoji.deny entry: a literal
+// emoji (🚀), code points (U+1F680, or U+1F9D1 U+200D U+1F4BB for a
+// sequence), a shortcode (:rocket:), or an emoji group or subgroup name
+// ("Travel & Places", "transport-air").
+func parseEmojiPattern(entry string) (emojiPattern, error) {
+	entry = strings.TrimSpace(entry)
+
+	switch {
+	case len(entry) > 2 && strings.EqualFold(entry[:2], "U+"):
+		var text []rune
+		for _, field := range strings.FieldsFunc(entry, func(r rune) bool { return r == ' ' || r == ',' }) {
+			hex, ok := strings.CutPrefix(strings.ToUpper(field), "U+")
+			cp, err := strconv.ParseUint(hex, 16, 32)
+			if !ok || err != nil {
+				return emojiPattern{}, fmt.Errorf("invalid code point %q", field)
+			}
+			text = append(text, rune(cp))
+		}
+		sequence, ok := rgiByKey[stripVariationSelectors(string(text))]
+		if !ok {
+			return emojiPattern{}, fmt.Errorf("%s is not an RGI emoji", entry)
+		}
+		return emojiPattern{sequence: sequence.Text}, nil
+	case len(entry) > 2 && strings.HasPrefix(entry, ":") && strings.HasSuffix(entry, ":"):
+		sequence, ok := emojiShortcodes[strings.Trim(entry, ":")]
+		if !ok {
+			return emojiPattern{}, fmt.Errorf("unknown shortcode %s", entry)
+		}
+		return emojiPattern{sequence: sequence}, nil
+	}
+
+	if e, ok := LeadingEmoji(entry); ok && e.Text == entry {
+		return emojiPattern{sequence: e.Qualified}, nil
+	}
+
+	for _, sequence := range rgiEmoji {
+		if strings.EqualFold(sequence.Group, entry) || strings.EqualFold(sequence.Subgroup, entry) {
+			return emojiPattern{group: entry}, nil
+		}
+	}
+
+	return emojiPattern{}, fmt.Errorf("not an emoji, code point, shortcode or emoji group")
+}
+
+func (p emojiPattern) matches(e Emoji) bool {
+	if p.group != "" {
+		return strings.EqualFold(e.Group, p.group) || strings.EqualFold(e.Subgroup, p.group)
+	}
+
+	if p.sequence == e.Qualified {
+		return true
+	}
+
+	// An entry without a skin tone matches every skin tone variant
+	if stripSkinTones(p.sequence) != p.sequence {
+		return false
+	}
+	return stripVariationSelectors(p.sequence) == stripVariationSelectors(stripSkinTones(e.Qualified))
+}
+
+// stripSkinTones removes the emoji modifiers (skin tones) from s.
+func stripSkinTones(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(emojiModifierTable, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,470 @@
+//go:build ignore
+
+// emoji_gen generates emoji_tables.go from the Unicode emoji data files:
+// the emoji properties from emoji-data.txt and the RGI emoji sequences with
+// their names and groups from emoji-test.txt. Search keywords come from the
+// CLDR English annotations, and shortcodes such as :rocket: from the gemoji
+// database used by GitHub.
+//
+// Usage:
+//
+//	go run emoji_gen.go [-version 15.1.0] [-cldr 44.1] [-gemoji v4.1.0] [-dir path]
+//
+// By default the files of the pinned versions are downloaded. Use -dir to
+// read emoji-data.txt, emoji-test.txt, en.xml and emoji.json from a local
+// directory instead.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// unicodeVersion is the pinned version of the Unicode emoji data.
+const unicodeVersion = "15.1.0"
+
+// cldrVersion is the pinned CLDR release of the English emoji annotations,
+// the one matching unicodeVersion.
+const cldrVersion = "44.1"
+
+// gemojiVersion is the pinned release of the gemoji shortcode database.
+const gemojiVersion = "v4.1.0"
+
+const output = "emoji_tables.go"
+
+// properties maps the emoji-data.txt properties to the generated table names.
+var properties = []struct {
+	name  string
+	table string
+	doc   string
+}{
+	{"Emoji", "emojiTable", "code points that are emoji, in text or emoji presentation"},
+	{"Emoji_Presentation", "emojiPresentationTable", "emoji displayed in emoji presentation by default"},
+	{"Emoji_Modifier", "emojiModifierTable", "the skin tone modifiers"},
+	{"Emoji_Modifier_Base", "emojiModifierBaseTable", "emoji that can be followed by a skin tone modifier"},
+	{"Emoji_Component", "emojiComponentTable", "code points used in emoji sequences"},
+	{"Extended_Pictographic", "extendedPictographicTable", "pictographic code points, including reserved ones"},
+}
+
+func main() {
+	version := flag.String("version", unicodeVersion, "Unicode version of the emoji data")
+	cldr := flag.String("cldr", cldrVersion, "CLDR release of the emoji keywords")
+	gemoji := flag.String("gemoji", gemojiVersion, "gemoji release of the shortcodes")
+	dir := flag.String("dir", "", "read the data files from this directory instead of unicode.org")
+	flag.Parse()
+
+	data, err := open(*dir, dataURL(*version))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ranges, err := parseProperties(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err = open(*dir, testURL(*version))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sequences, err := parseSequences(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err = open(*dir, annotationsURL(*cldr))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keywords, err := parseAnnotations(data, sequences)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data, err = open(*dir, gemojiURL(*gemoji))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shortcodes, err := parseGemoji(data, sequences)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := render(*version, *cldr, *gemoji, ranges, sequences, shortcodes, keywords)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func dataURL(version string) string {
+	return fmt.Sprintf("https://www.unicode.org/Public/%s/ucd/emoji/emoji-data.txt", version)
+}
+
+// testURL returns the location of emoji-test.txt, which is published with
+// the emoji data under the major.minor version only.
+func testURL(version string) string {
+	return fmt.Sprintf("https://www.unicode.org/Public/emoji/%s/emoji-test.txt", strings.TrimSuffix(version, ".0"))
+}
+
+// annotationsURL returns the location of the English emoji annotations of a
+// CLDR release, tagged release-44-1 for 44.1.
+func annotationsURL(release string) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/unicode-org/cldr/release-%s/common/annotations/en.xml", strings.ReplaceAll(release, ".", "-"))
+}
+
+func gemojiURL(release string) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/github/gemoji/%s/db/emoji.json", release)
+}
+
+// open returns the contents of a Unicode data file, either from dir or from url.
+func open(dir, url string) ([]byte, error) {
+	if dir != "" {
+		return os.ReadFile(filepath.Join(dir, path.Base(url)))
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// parseProperties reads `code points ; property # comment` lines and returns
+// the code point ranges of every property.
+func parseProperties(data []byte) (map[string][][2]rune, error) {
+	ranges := map[string][][2]rune{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		codePoints, property, ok := strings.Cut(line, ";")
+		if !ok {
+			return nil, fmt.Errorf("malformed line %q", scanner.Text())
+		}
+
+		lo, hi, err := parseRange(strings.TrimSpace(codePoints))
+		if err != nil {
+			return nil, err
+		}
+
+		property = strings.TrimSpace(property)
+		ranges[property] = append(ranges[property], [2]rune{lo, hi})
+	}
+
+	return ranges, scanner.Err()
+}
+
+// sequence is a fully-qualified emoji sequence of emoji-test.txt.
+type sequence struct {
+	text     string
+	version  string
+	name     string
+	group    string
+	subgroup string
+}
+
+// parseSequences reads the `code points ; status # emoji E<version> name`
+// lines of emoji-test.txt, below their `# group:` and `# subgroup:` headers,
+// and returns the fully-qualified emoji sequences in order. Components such
+// as skin tones on their own are left out, and the minimally and unqualified
+// forms differ from the fully-qualified ones only in their variation
+// selectors.
+func parseSequences(data []byte) ([]sequence, error) {
+	var sequences []sequence
+	var group, subgroup string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			if name, ok := strings.CutPrefix(strings.TrimSpace(comment), "group:"); ok {
+				group = strings.TrimSpace(name)
+			}
+			if name, ok := strings.CutPrefix(strings.TrimSpace(comment), "subgroup:"); ok {
+				subgroup = strings.TrimSpace(name)
+			}
+			continue
+		}
+
+		codePoints, status, ok := strings.Cut(line, ";")
+		if !ok {
+			return nil, fmt.Errorf("malformed line %q", scanner.Text())
+		}
+		if strings.TrimSpace(status) != "fully-qualified" {
+			continue
+		}
+
+		var text []rune
+		for _, field := range strings.Fields(codePoints) {
+			r, err := strconv.ParseUint(field, 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid code point %q", field)
+			}
+			text = append(text, rune(r))
+		}
+
+		fields := strings.Fields(comment)
+		if len(fields) < 3 || !strings.HasPrefix(fields[1], "E") {
+			return nil, fmt.Errorf("missing emoji version or name in line %q", scanner.Text())
+		}
+
+		sequences = append(sequences, sequence{
+			text:     string(text),
+			version:  strings.TrimPrefix(fields[1], "E"),
+			name:     strings.Join(fields[2:], " "),
+			group:    group,
+			subgroup: subgroup,
+		})
+	}
+
+	return sequences, scanner.Err()
+}
+
+// qualifiedForms maps the RGI sequences without U+FE0F to their
+// fully-qualified forms, to look up emoji written either way.
+func qualifiedForms(sequences []sequence) map[string]string {
+	qualified := map[string]string{}
+	for _, s := range sequences {
+		qualified[strings.ReplaceAll(s.text, "\uFE0F", "")] = s.text
+	}
+
+	return qualified
+}
+
+// parseAnnotations reads the CLDR annotations, such as
+// `<annotation cp="🚀">rocket | space</annotation>`, and maps every
+// fully-qualified emoji to its keywords. The text-to-speech entries repeat
+// the short names of emoji-test.txt and are left out, and so are emoji that
+// are not RGI sequences in the pinned Unicode version.
+func parseAnnotations(data []byte, sequences []sequence) (map[string][]string, error) {
+	var doc struct {
+		Annotations []struct {
+			CP   string `xml:"cp,attr"`
+			Type string `xml:"type,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"annotations>annotation"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse CLDR annotations: %w", err)
+	}
+
+	qualified := qualifiedForms(sequences)
+	keywords := map[string][]string{}
+	for _, annotation := range doc.Annotations {
+		text, ok := qualified[strings.ReplaceAll(annotation.CP, "\uFE0F", "")]
+		if !ok || annotation.Type == "tts" {
+			continue
+		}
+		for _, keyword := range strings.Split(annotation.Text, "|") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords[text] = append(keywords[text], keyword)
+			}
+		}
+	}
+
+	return keywords, nil
+}
+
+// parseGemoji reads the gemoji database and maps every alias to the
+// fully-qualified form of its emoji. Emoji that are not RGI sequences in the
+// pinned Unicode version are left out.
+func parseGemoji(data []byte, sequences []sequence) (map[string]string, error) {
+	var entries []struct {
+		Emoji   string   `json:"emoji"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse gemoji database: %w", err)
+	}
+
+	qualified := qualifiedForms(sequences)
+	shortcodes := map[string]string{}
+	for _, entry := range entries {
+		text, ok := qualified[strings.ReplaceAll(entry.Emoji, "\uFE0F", "")]
+		if !ok {
+			continue
+		}
+		for _, alias := range entry.Aliases {
+			shortcodes[alias] = text
+		}
+	}
+
+	return shortcodes, nil
+}
+
+func parseRange(s string) (rune, rune, error) {
+	first, last, isRange := strings.Cut(s, "..")
+	if !isRange {
+		last = first
+	}
+
+	lo, err := strconv.ParseUint(first, 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid code point %q", first)
+	}
+	hi, err := strconv.ParseUint(last, 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid code point %q", last)
+	}
+
+	return rune(lo), rune(hi), nil
+}
+
+func render(version, cldr, gemoji string, ranges map[string][][2]rune, sequences []sequence, shortcodes map[string]string, keywords map[string][]string) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, "// Code generated by emoji_gen.go; DO NOT EDIT.")
+	fmt.Fprintln(&b, "// Sources:")
+	fmt.Fprintf(&b, "//   %s\n", dataURL(version))
+	fmt.Fprintf(&b, "//   %s\n", testURL(version))
+	fmt.Fprintf(&b, "//   %s\n", annotationsURL(cldr))
+	fmt.Fprintf(&b, "//   %s\n\n", gemojiURL(gemoji))
+	fmt.Fprintln(&b, "package internal")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `import "unicode"`)
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// UnicodeVersion is the version of the Unicode emoji data the tables were generated from.")
+	fmt.Fprintf(&b, "const UnicodeVersion = %q\n", version)
+
+	for _, p := range properties {
+		r := merge(ranges[p.name])
+		if len(r) == 0 {
+			return nil, fmt.Errorf("no code points with property %s", p.name)
+		}
+
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "// %s holds the %s property: %s.\n", p.table, p.name, p.doc)
+		fmt.Fprintf(&b, "var %s = %s\n", p.table, rangeTable(r))
+	}
+
+	if len(sequences) == 0 {
+		return nil, fmt.Errorf("no fully-qualified emoji sequences")
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// rgiEmoji lists the fully-qualified RGI emoji sequences in emoji-test.txt order.")
+	fmt.Fprintln(&b, "var rgiEmoji = []emojiSequence{")
+	for _, s := range sequences {
+		fmt.Fprintf(&b, "\t{%+q, %q, %q, %q, %q}, // %s\n", s.text, s.version, s.name, s.group, s.subgroup, s.text)
+	}
+	fmt.Fprintln(&b, "}")
+
+	if len(shortcodes) == 0 {
+		return nil, fmt.Errorf("no shortcodes of RGI emoji")
+	}
+	aliases := make([]string, 0, len(shortcodes))
+	for alias := range shortcodes {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// emojiShortcodes maps gemoji shortcodes, without colons, to fully-qualified emoji.")
+	fmt.Fprintln(&b, "var emojiShortcodes = map[string]string{")
+	for _, alias := range aliases {
+		fmt.Fprintf(&b, "\t%q: %+q, // %s\n", alias, shortcodes[alias], shortcodes[alias])
+	}
+	fmt.Fprintln(&b, "}")
+
+	if len(keywords) == 0 {
+		return nil, fmt.Errorf("no CLDR keywords of RGI emoji")
+	}
+	tagged := make([]string, 0, len(keywords))
+	for text := range keywords {
+		tagged = append(tagged, text)
+	}
+	sort.Strings(tagged)
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// emojiKeywords maps fully-qualified emoji to their CLDR keywords, used by emoji search.")
+	fmt.Fprintln(&b, "var emojiKeywords = map[string][]string{")
+	for _, text := range tagged {
+		fmt.Fprintf(&b, "\t%+q: %#v, // %s\n", text, keywords[text], text)
+	}
+	fmt.Fprintln(&b, "}")
+
+	return format.Source(b.Bytes())
+}
+
+// merge sorts ranges and joins adjacent and overlapping ones.
+func merge(ranges [][2]rune) [][2]rune {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var merged [][2]rune
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1]+1 {
+			merged[n-1][1] = max(merged[n-1][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func rangeTable(ranges [][2]rune) string {
+	var r16, r32 strings.Builder
+	latinOffset := 0
+
+	for _, r := range ranges {
+		lo, hi := r[0], r[1]
+		if lo <= 0xFFFF && hi > 0xFFFF {
+			fmt.Fprintf(&r16, "\t\t{Lo: 0x%04X, Hi: 0x%04X, Stride: 1},\n", lo, 0xFFFF)
+			lo = 0x10000
+		}
+		if hi <= 0xFFFF {
+			fmt.Fprintf(&r16, "\t\t{Lo: 0x%04X, Hi: 0x%04X, Stride: 1},\n", lo, hi)
+			if hi <= unicode.MaxLatin1 {
+				latinOffset++
+			}
+			continue
+		}
+		fmt.Fprintf(&r32, "\t\t{Lo: 0x%05X, Hi: 0x%05X, Stride: 1},\n", lo, hi)
+	}
+
+	var b strings.Builder
+	b.WriteString("&unicode.RangeTable{\n")
+	if r16.Len() > 0 {
+		fmt.Fprintf(&b, "\tR16: []unicode.Range16{\n%s\t},\n", r16.String())
+	}
+	if r32.Len() > 0 {
+		fmt.Fprintf(&b, "\tR32: []unicode.Range32{\n%s\t},\n", r32.String())
+	}
+	if latinOffset > 0 {
+		fmt.Fprintf(&b, "\tLatinOffset: %d,\n", latinOffset)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
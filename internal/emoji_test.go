@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+)
+
+// TestEmojiTables tests the generated tables against known code points
+func TestEmojiTables(t *testing.T) {
+	tests := []struct {
+		r            rune
+		emoji        bool
+		presentation bool
+		pictographic bool
+	}{
+		{'🚀', true, true, true},
+		{'☀', true, false, true},  // text presentation by default
+		{'🫠', true, true, true},   // Emoji 14.0
+		{'🪿', true, true, true},   // Emoji 15.0
+		{'🫩', false, false, true}, // Emoji 16.0, after the pinned version
+		{'⌘', false, false, false},
+		{'⮕', false, false, false},
+		{'#', true, false, false},
+		{'A', false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("U+%04X", tt.r), func(t *testing.T) {
+			if got := unicode.Is(emojiTable, tt.r); got != tt.emoji {
+				t.Errorf("Emoji = %v, want %v", got, tt.emoji)
+			}
+			if got := unicode.Is(emojiPresentationTable, tt.r); got != tt.presentation {
+				t.Errorf("Emoji_Presentation = %v, want %v", got, tt.presentation)
+			}
+			if got := unicode.Is(extendedPictographicTable, tt.r); got != tt.pictographic {
+				t.Errorf("Extended_Pictographic = %v, want %v", got, tt.pictographic)
+			}
+		})
+	}
+}
+
+// TestLeadingEmoji tests that only complete RGI emoji sequences are matched
+func TestLeadingEmoji(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		text      string
+		qualified string
+		ok        bool
+	}{
+		{"single emoji", "🚀 Deploy", "🚀", "🚀", true},
+		{"keycap", "1\ufe0f\u20e3 Setup", "1\ufe0f\u20e3", "1\ufe0f\u20e3", true},
+		{"unqualified keycap", "1\u20e3 Setup", "1\u20e3", "1\ufe0f\u20e3", true},
+		{"flag", "🇺🇸 Release", "🇺🇸", "🇺🇸", true},
+		{"flag followed by a flag", "🇺🇸🇩🇪 Release", "🇺🇸", "🇺🇸", true},
+		{"tag sequence", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F Scotland", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", true},
+		{"modifier sequence", "👍🏽 Approve", "👍🏽", "👍🏽", true},
+		{"ZWJ sequence", "🧑\u200d💻 Code", "🧑\u200d💻", "🧑\u200d💻", true},
+		{"ZWJ sequence with modifier", "👩🏾\u200d🚀 Launch", "👩🏾\u200d🚀", "👩🏾\u200d🚀", true},
+		{"text presentation", "☀ Weather", "☀", "☀\ufe0f", true},
+		{"emoji presentation", "☀\ufe0f Weather", "☀\ufe0f", "☀\ufe0f", true},
+		{"no space", "✅Check", "✅", "✅", true},
+		{"lone ZWJ", "\u200d🚀 Deploy", "", "", false},
+		{"lone skin tone", "🏽 Deploy", "", "", false},
+		{"lone regional indicator", "🇺 Deploy", "", "", false},
+		{"digit", "1 Setup", "", "", false},
+		{"non-RGI ZWJ sequence", "🚀\u200d🔥 Deploy", "", "", false},
+		{"emoji with combining mark", "🚀\u0301 Deploy", "", "", false},
+		{"empty", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emoji, ok := LeadingEmoji(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("LeadingEmoji(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+			}
+			if emoji.Text != tt.text || emoji.Qualified != tt.qualified {
+				t.Errorf("LeadingEmoji(%q) = %+q, want {%+q %+q}", tt.input, emoji, tt.text, tt.qualified)
+			}
+		})
+	}
+}
+
+// TestParseEmojiPattern tests the entry forms accepted by emoji.allow and emoji.deny
+func TestParseEmojiPattern(t *testing.T) {
+	rocket, _ := LeadingEmoji("🚀")
+	technologist, _ := LeadingEmoji("🧑\u200d💻")
+	eggplant, _ := LeadingEmoji("🍆")
+	thumbsUp, _ := LeadingEmoji("👍🏽")
+	pointingUp, _ := LeadingEmoji("☝🏿")
+
+	tests := []struct {
+		entry   string
+		matches []Emoji
+		misses  []Emoji
+		error   string
+	}{
+		{entry: "🚀", matches: []Emoji{rocket}, misses: []Emoji{eggplant}},
+		{entry: "U+1F680", matches: []Emoji{rocket}, misses: []Emoji{eggplant}},
+		{entry: "u+1f680", matches: []Emoji{rocket}},
+		{entry: "U+1F9D1 U+200D U+1F4BB", matches: []Emoji{technologist}, misses: []Emoji{rocket}},
+		{entry: ":rocket:", matches: []Emoji{rocket}, misses: []Emoji{eggplant}},
+		{entry: "👍", matches: []Emoji{thumbsUp}, misses: []Emoji{rocket}},
+		{entry: "👍🏻", misses: []Emoji{thumbsUp}},
+		{entry: ":point_up:", matches: []Emoji{pointingUp}},
+		{entry: "Travel & Places", matches: []Emoji{rocket}, misses: []Emoji{eggplant}},
+		{entry: "food-vegetable", matches: []Emoji{eggplant}, misses: []Emoji{rocket}},
+		{entry: "U+1F68", error: "U+1F68 is not an RGI emoji"},
+		{entry: "U+XYZ", error: `invalid code point "U+XYZ"`},
+		{entry: ":rockt:", error: "unknown shortcode :rockt:"},
+		{entry: "🚀 Deploy", error: "not an emoji, code point, shortcode or emoji group"},
+		{entry: "Vehicles", error: "not an emoji, code point, shortcode or emoji group"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			pattern, err := parseEmojiPattern(tt.entry)
+			if tt.error != "" {
+				if err == nil || err.Error() != tt.error {
+					t.Fatalf("parseEmojiPattern(%q) error = %v, want %q", tt.entry, err, tt.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEmojiPattern(%q) failed: %v", tt.entry, err)
+			}
+
+			for _, e := range tt.matches {
+				if !pattern.matches(e) {
+					t.Errorf("%q does not match %s", tt.entry, e.Text)
+				}
+			}
+			for _, e := range tt.misses {
+				if pattern.matches(e) {
+					t.Errorf("%q matches %s", tt.entry, e.Text)
+				}
+			}
+		})
+	}
+}
+
+// TestLeadingShortcode tests recognition of GitHub shortcodes at the start of a name
+func TestLeadingShortcode(t *testing.T) {
+	tests := []struct {
+		name      string
+		shortcode string
+		emoji     string
+		ok        bool
+	}{
+		{":rocket: Deploy", ":rocket:", "🚀", true},
+		{":+1:Approve", ":+1:", "👍", true},
+		{":white_check_mark: Test", ":white_check_mark:", "✅", true},
+		{":notacode: Checkout", "", "", false},
+		{":rocket Deploy", "", "", false},
+		{"Deploy :rocket:", "", "", false},
+		{"🚀 Deploy", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortcode, emoji, ok := LeadingShortcode(tt.name)
+			if shortcode != tt.shortcode || emoji != tt.emoji || ok != tt.ok {
+				t.Errorf("LeadingShortcode(%q) = %q, %q, %v, want %q, %q, %v",
+					tt.name, shortcode, emoji, ok, tt.shortcode, tt.emoji, tt.ok)
+			}
+		})
+	}
+}
+
+// TestNeedsPresentationSelector tests detection of emoji that render as text without U+FE0F
+func TestNeedsPresentationSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"\u2708 Deploy", true},
+		{"\u2708\ufe0f Deploy", false},
+		{"\u2600 Nightly", true},
+		{"1\u20e3 First", true},
+		{"1\ufe0f\u20e3 First", false},
+		{"\U0001F3F3\u200d\U0001F308 Pride", true},
+		{"🚀 Launch", false},
+		{"🚀\ufe0f Launch", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := LeadingEmoji(tt.name)
+			if !ok {
+				t.Fatalf("LeadingEmoji(%q) found no emoji", tt.name)
+			}
+			if got := needsPresentationSelector(e); got != tt.expected {
+				t.Errorf("needsPresentationSelector(%q) = %v, want %v", e.Text, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/runenames"
+)
+
+// lookalikes maps symbols that resemble an emoji, but are not one, to the
+// emoji they are usually meant to be.
+var lookalikes = map[rune]string{
+	'→': "➡️",
+	'★': "⭐",
+	'☆': "⭐",
+	'✓': "✔️",
+	'✗': "❌",
+	'✘': "❌",
+	'●': "⚫",
+	'○': "⚪",
+}
+
+// describeRune names a code point by its Unicode character name, such as
+// "PLACE OF INTEREST SIGN", or by the label of a range whose characters have
+// no individual names, such as "<control>".
+func describeRune(r rune) string {
+	if name := runenames.Name(r); name != "" {
+		return name
+	}
+
+	return "unassigned"
+}
+
+// describeGrapheme lists the code points of a grapheme cluster with their
+// Unicode names, or the CLDR name of the emoji it is.
+func describeGrapheme(cluster string) string {
+	if e, ok := LeadingEmoji(cluster); ok && e.Text == cluster {
+		return fmt.Sprintf("\"%s\" is %s (%s)", cluster, CodePoints(cluster), e.Name)
+	}
+
+	points := make([]string, 0, len(cluster))
+	for _, r := range cluster {
+		points = append(points, fmt.Sprintf("U+%04X (%s)", r, describeRune(r)))
+	}
+	return fmt.Sprintf("%q is %s", cluster, strings.Join(points, " "))
+}
+
+// explainSlot describes the grapheme at the start or end of a name where the
+// placement expects an emoji, and why it is not one. It returns "" for slots
+// holding an emoji or shortcode and for the anywhere placement.
+func explainSlot(name string, slot emojiSlot) string {
+	if slot.ok || slot.Shortcode != "" || slot.Side == sideAnywhere {
+		return ""
+	}
+
+	clusters := graphemes(name)
+	if len(clusters) == 0 {
+		return ""
+	}
+
+	cluster, position := clusters[0], "First"
+	trimmed, emoji, ok := strings.TrimLeftFunc(name, unicode.IsSpace), Emoji{}, false
+	if slot.Side == sideEnd {
+		cluster, position = clusters[len(clusters)-1], "Last"
+		trimmed = strings.TrimRightFunc(name, unicode.IsSpace)
+		emoji, ok = TrailingEmoji(trimmed)
+	} else {
+		emoji, ok = LeadingEmoji(trimmed)
+	}
+
+	reason := "not an emoji"
+	r, _ := utf8.DecodeRuneInString(cluster)
+	switch {
+	case ok && trimmed != name && slot.Side == sideEnd:
+		reason = fmt.Sprintf("emoji %s is followed by whitespace", emoji.Text)
+	case ok && trimmed != name:
+		reason = fmt.Sprintf("emoji %s is preceded by whitespace", emoji.Text)
+	case lookalikes[r] != "" && utf8.RuneCountInString(stripVariationSelectors(cluster)) == 1:
+		reason = fmt.Sprintf("not an emoji, did you mean %s?", lookalikes[r])
+	case unicode.Is(extendedPictographicTable, r) && utf8.RuneCountInString(cluster) > 1:
+		reason = "not a recommended (RGI) emoji sequence"
+	case unicode.Is(emojiTable, r) || unicode.Is(emojiComponentTable, r):
+		reason = "an emoji component, not an emoji on its own"
+	}
+
+	return fmt.Sprintf("%s character %s: %s", position, describeGrapheme(cluster), reason)
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"testing"
+)
+
+// TestExplainSlot tests the explanations of names without an emoji where one is expected
+func TestExplainSlot(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement Placement
+		expected  []string
+	}{
+		{"Deploy", PlacementPrefix, []string{`First character "D" is U+0044 (LATIN CAPITAL LETTER D): not an emoji`}},
+		{" 🚀 Deploy", PlacementPrefix, []string{`First character " " is U+0020 (SPACE): emoji 🚀 is preceded by whitespace`}},
+		{"Deploy 🚀 ", PlacementSuffix, []string{`Last character " " is U+0020 (SPACE): emoji 🚀 is followed by whitespace`}},
+		{"★ Deploy", PlacementPrefix, []string{`First character "★" is U+2605 (BLACK STAR): not an emoji, did you mean ⭐?`}},
+		{"⌘ Deploy", PlacementPrefix, []string{`First character "⌘" is U+2318 (PLACE OF INTEREST SIGN): not an emoji`}},
+		{"1 Deploy", PlacementPrefix, []string{`First character "1" is U+0031 (DIGIT ONE): an emoji component, not an emoji on its own`}},
+		{"🏽 Deploy", PlacementPrefix, []string{`First character "🏽" is U+1F3FD (EMOJI MODIFIER FITZPATRICK TYPE-4): an emoji component, not an emoji on its own`}},
+		{"Deploy", PlacementWrap, []string{
+			`First character "D" is U+0044 (LATIN CAPITAL LETTER D): not an emoji`,
+			`Last character "y" is U+0079 (LATIN SMALL LETTER Y): not an emoji`,
+		}},
+		{"🚀 Deploy", PlacementWrap, []string{`Last character "y" is U+0079 (LATIN SMALL LETTER Y): not an emoji`}},
+		{"Deploy", PlacementAnywhere, nil},
+		{":rocket: Deploy", PlacementPrefix, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.placement)+" "+tt.name, func(t *testing.T) {
+			var got []string
+			for _, slot := range emojiSlots(tt.name, tt.placement) {
+				if explanation := explainSlot(tt.name, slot); explanation != "" {
+					got = append(got, explanation)
+				}
+			}
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("explanations = %q, want %q", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("explanation %d = %q, want %q", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestDescribeGrapheme tests naming emoji, sequences and other characters
+func TestDescribeGrapheme(t *testing.T) {
+	tests := []struct {
+		cluster  string
+		expected string
+	}{
+		{"🚀", `"🚀" is U+1F680 (rocket)`},
+		{"✈", `"✈" is U+2708 (airplane)`},
+		{"🧑\u200d💻", "\"🧑\u200d💻\" is U+1F9D1 U+200D U+1F4BB (technologist)"},
+		{"✓\uFE0E", "\"✓\uFE0E\" is U+2713 (CHECK MARK) U+FE0E (VARIATION SELECTOR-15)"},
+		{"$", `"$" is U+0024 (DOLLAR SIGN)`},
+		{"⌘", `"⌘" is U+2318 (PLACE OF INTEREST SIGN)`},
+		{"\u0378", `"\u0378" is U+0378 (unassigned)`},
+	}
+
+	for _, tt := range tests {
+		if got := describeGrapheme(tt.cluster); got != tt.expected {
+			t.Errorf("describeGrapheme(%q) = %s, want %s", tt.cluster, got, tt.expected)
+		}
+	}
+}
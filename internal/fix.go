@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"strings"
+)
+
+// Fix is a text edit that resolves a violation: the first occurrence of Old
+// at or after Line and Column of the workflow file is replaced by New. Line
+// and Column are 1-based, with Column counted in characters like yaml.Node.
+type Fix struct {
+	Line   int
+	Column int
+	Old    string
+	New    string
+}
+
+// ApplyFixes applies fixes to the source of a workflow file in order and
+// returns the fixed source along with the number of fixes applied. Fixes whose
+// text is no longer found, for example in an escaped or multi-line name, are
+// skipped.
+func ApplyFixes(source []byte, fixes []Fix) ([]byte, int) {
+	lines := strings.SplitAfter(string(source), "\n")
+
+	applied := 0
+	for _, fix := range fixes {
+		if fix.Line < 1 || fix.Line > len(lines) {
+			continue
+		}
+
+		line := lines[fix.Line-1]
+		start := columnOffset(line, fix.Column)
+		index := strings.Index(line[start:], fix.Old)
+		if index == -1 {
+			continue
+		}
+
+		offset := start + index
+		lines[fix.Line-1] = line[:offset] + fix.New + line[offset+len(fix.Old):]
+		applied++
+	}
+
+	return []byte(strings.Join(lines, "")), applied
+}
+
+// columnOffset returns the byte offset of the 1-based character column in line.
+func columnOffset(line string, column int) int {
+	characters := 1
+	for offset := range line {
+		if characters == column {
+			return offset
+		}
+		characters++
+	}
+
+	return len(line)
+}
+
+// Fixes returns the fixes of the given violations.
+func Fixes(violations []Violation) []Fix {
+	var fixes []Fix
+	for _, v := range violations {
+		if v.Fix != nil {
+			fixes = append(fixes, *v.Fix)
+		}
+	}
+
+	return fixes
+}
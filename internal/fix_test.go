@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"os"
+	"testing"
+)
+
+// TestApplyFixes tests that the fixes of a lint report rewrite only the names they target
+func TestApplyFixes(t *testing.T) {
+	tests := []struct {
+		name            string
+		workflowFile    string
+		fixedFile       string
+		expectedApplied int
+	}{
+		{"shortcodes", "testdata/shortcode_workflow.yml", "testdata/shortcode_workflow.fixed.yml", 3},
+		{"separators", "testdata/separator_workflow.yml", "testdata/separator_workflow.fixed.yml", 5},
+		{"invisible characters", "testdata/invisible_workflow.yml", "testdata/invisible_workflow.fixed.yml", 3},
+		{"text presentation", "testdata/text_presentation_workflow.yml", "testdata/text_presentation_workflow.fixed.yml", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := ParseYAML(tt.workflowFile)
+			if err != nil {
+				t.Fatalf("Failed to parse workflow: %v", err)
+			}
+
+			violations, err := LintWorkflow(node)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			source, err := os.ReadFile(tt.workflowFile)
+			if err != nil {
+				t.Fatalf("Failed to read workflow: %v", err)
+			}
+
+			fixed, applied := ApplyFixes(source, Fixes(violations))
+			if applied != tt.expectedApplied {
+				t.Errorf("ApplyFixes() applied %d fixes, want %d", applied, tt.expectedApplied)
+			}
+
+			expected, err := os.ReadFile(tt.fixedFile)
+			if err != nil {
+				t.Fatalf("Failed to read fixed workflow: %v", err)
+			}
+			if string(fixed) != string(expected) {
+				t.Errorf("ApplyFixes() =\n%s\nwant\n%s", fixed, expected)
+			}
+		})
+	}
+}
+
+// TestApplyFixes_Positions tests column handling and fixes that no longer apply
+func TestApplyFixes_Positions(t *testing.T) {
+	source := "name: 🧪 :test_tube: x\n"
+
+	tests := []struct {
+		name     string
+		fix      Fix
+		expected string
+		applied  int
+	}{
+		{"after multibyte column", Fix{Line: 1, Column: 8, Old: ":test_tube:", New: "🧪"}, "name: 🧪 🧪 x\n", 1},
+		{"before column", Fix{Line: 1, Column: 10, Old: "name", New: "title"}, source, 0},
+		{"missing text", Fix{Line: 1, Column: 7, Old: ":rocket:", New: "🚀"}, source, 0},
+		{"line out of range", Fix{Line: 3, Column: 1, Old: "x", New: "y"}, source, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, applied := ApplyFixes([]byte(source), []Fix{tt.fix})
+			if string(fixed) != tt.expected || applied != tt.applied {
+				t.Errorf("ApplyFixes() = %q, %d, want %q, %d", fixed, applied, tt.expected, tt.applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeBreak is the Grapheme_Cluster_Break property of a code point, see
+// UAX #29 (https://www.unicode.org/reports/tr29/).
+type graphemeBreak int
+
+const (
+	gbOther graphemeBreak = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+const (
+	zeroWidthJoiner     = '\u200D'
+	zeroWidthNonJoiner  = '\u200C'
+	hangulSyllableFirst = 0xAC00
+	hangulSyllableLast  = 0xD7A3
+	hangulTCount        = 28
+)
+
+// graphemeBreakOf derives the Grapheme_Cluster_Break property from the
+// general categories and properties of the unicode package, together with
+// the generated emoji tables. Prepend only covers the prepended concatenation
+// marks, which is all that matters at the start of a name.
+func graphemeBreakOf(r rune) graphemeBreak {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == zeroWidthJoiner:
+		return gbZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case r == zeroWidthNonJoiner,
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend, emojiModifierTable):
+		return gbExtend
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r):
+		return gbPrepend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= hangulSyllableFirst && r <= hangulSyllableLast:
+		if (r-hangulSyllableFirst)%hangulTCount == 0 {
+			return gbLV
+		}
+		return gbLVT
+	}
+
+	return gbOther
+}
+
+// firstGrapheme returns the extended grapheme cluster at the start of s.
+func firstGrapheme(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return ""
+	}
+
+	prev := graphemeBreakOf(r)
+	// pictographic reports whether the cluster so far ends in
+	// Extended_Pictographic Extend* or Extended_Pictographic Extend* ZWJ (GB11).
+	pictographic := unicode.Is(extendedPictographicTable, r)
+	regionalIndicators := 0
+	if prev == gbRegionalIndicator {
+		regionalIndicators = 1
+	}
+
+	end := size
+	for end < len(s) {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		next := graphemeBreakOf(r)
+		nextPictographic := unicode.Is(extendedPictographicTable, r)
+		if graphemeBoundary(prev, next, prev == gbZWJ && pictographic && nextPictographic, regionalIndicators) {
+			break
+		}
+
+		pictographic = nextPictographic || pictographic && (next == gbZWJ || next == gbExtend && prev != gbZWJ)
+		if next == gbRegionalIndicator {
+			regionalIndicators++
+		}
+
+		prev = next
+		end += size
+	}
+
+	return s[:end]
+}
+
+// graphemes splits s into extended grapheme clusters.
+func graphemes(s string) []string {
+	var clusters []string
+	for s != "" {
+		cluster := firstGrapheme(s)
+		clusters = append(clusters, cluster)
+		s = s[len(cluster):]
+	}
+
+	return clusters
+}
+
+// graphemeBoundary applies the rules GB3 to GB999 between two code points.
+// joinsPictographic reports whether GB11 applies: the cluster so far ends in
+// Extended_Pictographic Extend* ZWJ and the next code point is
+// Extended_Pictographic. regionalIndicators counts the regional indicators
+// the cluster holds.
+func graphemeBoundary(prev, next graphemeBreak, joinsPictographic bool, regionalIndicators int) bool {
+	switch {
+	case prev == gbCR && next == gbLF: // GB3
+		return false
+	case prev == gbCR || prev == gbLF || prev == gbControl: // GB4
+		return true
+	case next == gbCR || next == gbLF || next == gbControl: // GB5
+		return true
+	case prev == gbL && (next == gbL || next == gbV || next == gbLV || next == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (next == gbV || next == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && next == gbT: // GB8
+		return false
+	case next == gbExtend || next == gbZWJ || next == gbSpacingMark: // GB9, GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case joinsPictographic: // GB11
+		return false
+	case prev == gbRegionalIndicator && next == gbRegionalIndicator: // GB12, GB13
+		return regionalIndicators%2 == 0
+	}
+
+	return true // GB999
+}
@@ -0,0 +1,33 @@
+package internal
+
+import "testing"
+
+// TestFirstGrapheme tests segmentation of the leading extended grapheme cluster
+func TestFirstGrapheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ASCII", "Deploy", "D"},
+		{"CRLF", "\r\nx", "\r\n"},
+		{"control", "\tx", "\t"},
+		{"combining mark", "e\u0301x", "e\u0301"},
+		{"Hangul syllable", "각x", "각"},
+		{"spacing mark", "किx", "कि"},
+		{"prepended concatenation mark", "\u0600١x", "\u0600١"},
+		{"ZWJ sequence", "👨\u200d👩\u200d👧 Family", "👨\u200d👩\u200d👧"},
+		{"ZWJ before a non-pictograph", "🚀\u200dx", "🚀\u200d"},
+		{"lone ZWJ", "\u200d🚀", "\u200d"},
+		{"regional indicator pairs", "🇩🇪🇫🇷🇮", "🇩🇪"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstGrapheme(tt.input); got != tt.expected {
+				t.Errorf("firstGrapheme(%+q) = %+q, want %+q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
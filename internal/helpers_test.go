@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+)
+
+// assertRuleViolations checks the violations of rule, formatted as
+// "[Type] Identifier: Msg (severity)", against expected, in order. An empty
+// rule checks every violation.
+func assertRuleViolations(t *testing.T, violations []Violation, rule string, expected []string) {
+	t.Helper()
+
+	var got []string
+	for _, v := range violations {
+		if rule == "" || v.Rule == rule {
+			got = append(got, formatViolation(v))
+		}
+	}
+
+	assertLines(t, got, expected)
+}
+
+// assertCrossFileViolations checks the violations of rule found across files,
+// as returned by Consistency.Violations, against expected. They are formatted
+// as "file:line [Type] Identifier: Msg (severity)" and ordered by files. An
+// empty rule checks every violation.
+func assertCrossFileViolations(t *testing.T, files []string, violations map[string][]Violation, rule string, expected []string) {
+	t.Helper()
+
+	var got []string
+	for _, file := range files {
+		for _, v := range violations[file] {
+			if rule == "" || v.Rule == rule {
+				got = append(got, fmt.Sprintf("%s:%d %s", file, v.Line, formatViolation(v)))
+			}
+		}
+	}
+
+	assertLines(t, got, expected)
+}
+
+func formatViolation(v Violation) string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", v.Type, v.Identifier, v.Msg, v.Severity)
+}
+
+func assertLines(t *testing.T, got, expected []string) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %q", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("violation %d = %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+// compileConfig compiles the patterns of a config built in the test, as
+// resolving a loaded config does.
+func compileConfig(t *testing.T, cfg *Config) *Config {
+	t.Helper()
+
+	if err := cfg.compile(); err != nil {
+		t.Fatalf("Failed to compile config: %v", err)
+	}
+
+	return cfg
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SkinTone is the skin tone policy for emoji that support skin tones.
+type SkinTone string
+
+const (
+	// SkinToneAny allows every skin tone.
+	SkinToneAny SkinTone = "any"
+	// SkinToneDefault requires the default (yellow) skin tone: 👍, not 👍🏽.
+	SkinToneDefault SkinTone = "default"
+	// SkinToneConsistent requires every emoji of a workflow file to use the
+	// skin tone of the first one that has a skin tone.
+	SkinToneConsistent SkinTone = "consistent"
+)
+
+// SkinTones lists every valid skin tone policy.
+var SkinTones = []SkinTone{SkinToneAny, SkinToneDefault, SkinToneConsistent}
+
+// modifierNames are the CLDR names of the emoji modifiers.
+var modifierNames = map[rune]string{
+	'\U0001F3FB': "light skin tone",
+	'\U0001F3FC': "medium-light skin tone",
+	'\U0001F3FD': "medium skin tone",
+	'\U0001F3FE': "medium-dark skin tone",
+	'\U0001F3FF': "dark skin tone",
+}
+
+// Gendered code points of ZWJ sequences: man, woman, male sign and female
+// sign, and the gender-neutral person that replaces man and woman.
+const (
+	man        = '\U0001F468'
+	woman      = '\U0001F469'
+	maleSign   = '♂'
+	femaleSign = '♀'
+	person     = '\U0001F9D1'
+)
+
+// skinTone returns the first skin tone of an emoji, or 0 if it has none.
+func skinTone(e Emoji) rune {
+	for _, r := range e.Qualified {
+		if unicode.Is(emojiModifierTable, r) {
+			return r
+		}
+	}
+
+	return 0
+}
+
+// withSkinTone returns the fully-qualified form of an emoji with every skin
+// tone replaced by tone, or removed when tone is 0. It reports false when
+// that form is not an RGI emoji.
+func withSkinTone(e Emoji, tone rune) (string, bool) {
+	replaced := strings.Map(func(r rune) rune {
+		if unicode.Is(emojiModifierTable, r) {
+			if tone == 0 {
+				return -1
+			}
+			return tone
+		}
+		return r
+	}, e.Qualified)
+
+	sequence, ok := rgiByKey[stripVariationSelectors(replaced)]
+	return sequence.Text, ok
+}
+
+// genderNeutral returns the gender-neutral form of a gendered ZWJ sequence,
+// keeping its skin tone: 🙋‍♀️ becomes 🙋 and 👨‍💻 becomes 🧑‍💻. It reports
+// false for emoji that are not gendered ZWJ sequences or have no
+// gender-neutral RGI form, such as 👨‍👩‍👧.
+func genderNeutral(e Emoji) (string, bool) {
+	key := stripVariationSelectors(e.Qualified)
+	if !strings.Contains(key, "\u200d") {
+		return "", false
+	}
+
+	if trimmed, ok := strings.CutSuffix(key, "\u200d"+string(maleSign)); ok {
+		key = trimmed
+	} else if trimmed, ok := strings.CutSuffix(key, "\u200d"+string(femaleSign)); ok {
+		key = trimmed
+	} else if first := []rune(key)[0]; first == man || first == woman {
+		key = string(person) + key[len(string(first)):]
+	} else {
+		return "", false
+	}
+
+	sequence, ok := rgiByKey[key]
+	return sequence.Text, ok
+}
+
+// checkInclusive returns the violations of emoji.skinTone and
+// emoji.genderNeutral by an emoji, with fixes to be located by the caller.
+func (l *linter) checkInclusive(e Emoji) []Violation {
+	var violations []Violation
+	tone := skinTone(e)
+
+	switch l.cfg.Emoji.SkinTone {
+	case SkinToneDefault:
+		if tone == 0 {
+			break
+		}
+		v := Violation{Rule: RuleInclusive, Msg: fmt.Sprintf("Emoji %s has a %s, emoji.skinTone requires the default skin tone", e.Text, modifierNames[tone])}
+		if neutral, ok := withSkinTone(e, 0); ok {
+			v.Msg += fmt.Sprintf(", use %s", neutral)
+			v.Fix = &Fix{Old: e.Text, New: neutral}
+		}
+		violations = append(violations, v)
+	case SkinToneConsistent:
+		if tone == 0 {
+			break
+		}
+		if l.skinTone == 0 {
+			l.skinTone, l.toneEmoji = tone, e.Text
+			break
+		}
+		if tonesMatch(e, l.skinTone) {
+			break
+		}
+		v := Violation{Rule: RuleInclusive, Msg: fmt.Sprintf("Emoji %s has a %s, but %s earlier in the workflow has a %s", e.Text, modifierNames[tone], l.toneEmoji, modifierNames[l.skinTone])}
+		if consistent, ok := withSkinTone(e, l.skinTone); ok {
+			v.Msg += fmt.Sprintf(", use %s", consistent)
+			v.Fix = &Fix{Old: e.Text, New: consistent}
+		}
+		violations = append(violations, v)
+	}
+
+	if l.cfg.Emoji.GenderNeutral {
+		if neutral, ok := genderNeutral(e); ok {
+			violations = append(violations, Violation{
+				Rule: RuleInclusive,
+				Msg:  fmt.Sprintf("Emoji %s is gendered, emoji.genderNeutral prefers %s", e.Text, neutral),
+				Fix:  &Fix{Old: e.Text, New: neutral},
+			})
+		}
+	}
+
+	return violations
+}
+
+// tonesMatch reports whether every skin tone of an emoji is tone.
+func tonesMatch(e Emoji, tone rune) bool {
+	for _, r := range e.Qualified {
+		if unicode.Is(emojiModifierTable, r) && r != tone {
+			return false
+		}
+	}
+
+	return true
+}
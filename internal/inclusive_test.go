@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGenderNeutral tests replacing gendered ZWJ sequences with their gender-neutral form
+func TestGenderNeutral(t *testing.T) {
+	tests := []struct {
+		emoji    string
+		expected string
+		ok       bool
+	}{
+		{"🙋\u200d♀️", "🙋", true},
+		{"👮🏽\u200d♂️", "👮🏽", true},
+		{"👨\u200d💻", "🧑\u200d💻", true},
+		{"👩🏿\u200d⚕️", "🧑🏿\u200d⚕️", true},
+		{"👨\u200d👩\u200d👧", "", false},
+		{"🧑\u200d💻", "", false},
+		{"🚀", "", false},
+	}
+
+	for _, tt := range tests {
+		e, ok := LeadingEmoji(tt.emoji)
+		if !ok {
+			t.Fatalf("LeadingEmoji(%q) found no emoji", tt.emoji)
+		}
+		if got, ok := genderNeutral(e); got != tt.expected || ok != tt.ok {
+			t.Errorf("genderNeutral(%s) = %q, %v, want %q, %v", tt.emoji, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+// TestWithSkinTone tests removing and replacing skin tones
+func TestWithSkinTone(t *testing.T) {
+	tests := []struct {
+		emoji    string
+		tone     rune
+		expected string
+	}{
+		{"👍🏽", 0, "👍"},
+		{"✌🏽", 0, "✌️"},
+		{"👍🏽", '\U0001F3FF', "👍🏿"},
+		{"🧑🏻\u200d🤝\u200d🧑🏿", '\U0001F3FD', "🧑🏽\u200d🤝\u200d🧑🏽"},
+	}
+
+	for _, tt := range tests {
+		e, ok := LeadingEmoji(tt.emoji)
+		if !ok {
+			t.Fatalf("LeadingEmoji(%q) found no emoji", tt.emoji)
+		}
+		if got, ok := withSkinTone(e, tt.tone); got != tt.expected || !ok {
+			t.Errorf("withSkinTone(%s, %U) = %q, %v, want %q", tt.emoji, tt.tone, got, ok, tt.expected)
+		}
+	}
+}
+
+// TestLintWorkflowReport_Inclusive tests emoji.skinTone and emoji.genderNeutral and their fixes
+func TestLintWorkflowReport_Inclusive(t *testing.T) {
+	tests := []struct {
+		name          string
+		skinTone      SkinTone
+		genderNeutral bool
+		expected      []string
+		fixed         []string
+	}{
+		{name: "any", skinTone: SkinToneAny},
+		{
+			name:     "default",
+			skinTone: SkinToneDefault,
+			expected: []string{
+				"Emoji 👍🏽 has a medium skin tone, emoji.skinTone requires the default skin tone, use 👍",
+				"Emoji 👷🏿\u200d♂️ has a dark skin tone, emoji.skinTone requires the default skin tone, use 👷\u200d♂️",
+				"Emoji 👌🏽 has a medium skin tone, emoji.skinTone requires the default skin tone, use 👌",
+			},
+			fixed: []string{"name: 👍 Review", "- name: 👷\u200d♂️ Construct", "- name: 👌 Done"},
+		},
+		{
+			name:     "consistent",
+			skinTone: SkinToneConsistent,
+			expected: []string{
+				"Emoji 👷🏿\u200d♂️ has a dark skin tone, but 👍🏽 earlier in the workflow has a medium skin tone, use 👷🏽\u200d♂️",
+			},
+			fixed: []string{"- name: 👷🏽\u200d♂️ Construct"},
+		},
+		{
+			name:          "gender neutral",
+			skinTone:      SkinToneAny,
+			genderNeutral: true,
+			expected: []string{
+				"Emoji 👩\u200d💻 is gendered, emoji.genderNeutral prefers 🧑\u200d💻",
+				"Emoji 👷🏿\u200d♂️ is gendered, emoji.genderNeutral prefers 👷🏿",
+				"Emoji 🙋\u200d♀️ is gendered, emoji.genderNeutral prefers 🙋",
+			},
+			fixed: []string{"name: 🧑\u200d💻 Build", "- name: 👷🏿 Construct", "- name: 🙋 Ask"},
+		},
+	}
+
+	node, err := ParseYAML("testdata/inclusive_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+	source, err := os.ReadFile("testdata/inclusive_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Emoji.SkinTone = tt.skinTone
+			cfg.Emoji.GenderNeutral = tt.genderNeutral
+
+			report, err := LintWorkflowReport(node, cfg)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			if len(report.Violations) != len(tt.expected) {
+				t.Fatalf("Expected %d violations, got %d: %+v", len(tt.expected), len(report.Violations), report.Violations)
+			}
+			for i, v := range report.Violations {
+				if v.Msg != tt.expected[i] || v.Rule != RuleInclusive {
+					t.Errorf("violation %d = %q (%s), want %q (%s)", i, v.Msg, v.Rule, tt.expected[i], RuleInclusive)
+				}
+			}
+
+			fixed, applied := ApplyFixes(source, Fixes(report.Violations))
+			if applied != len(tt.fixed) {
+				t.Errorf("ApplyFixes() applied %d fixes, want %d", applied, len(tt.fixed))
+			}
+			for _, line := range tt.fixed {
+				if !containsLine(string(fixed), line) {
+					t.Errorf("fixed workflow has no line %q:\n%s", line, fixed)
+				}
+			}
+		})
+	}
+}
+
+// containsLine reports whether s has a line that is line after indentation.
+func containsLine(s, line string) bool {
+	for _, l := range strings.Split(s, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// invisibleCharacter is an invisible code point found in a name and its byte
+// offset.
+type invisibleCharacter struct {
+	Rune   rune
+	Offset int
+}
+
+// invisibleCharacters returns the zero-width, bidi control and other
+// invisible format characters of name. Code points that belong to an RGI
+// emoji sequence, such as the ZWJ of 🧑‍💻 or the tags of a subdivision flag, and joiners
+// between letters, as used by Indic and Arabic scripts, are left alone.
+func invisibleCharacters(name string) []invisibleCharacter {
+	var found []invisibleCharacter
+
+	offset := 0
+	for _, cluster := range graphemes(name) {
+		if _, ok := LeadingEmoji(cluster); ok {
+			offset += len(cluster)
+			continue
+		}
+
+		for i, r := range cluster {
+			if isInvisible(r) && !joinsLetters(name, offset+i) {
+				found = append(found, invisibleCharacter{Rune: r, Offset: offset + i})
+			}
+		}
+		offset += len(cluster)
+	}
+
+	return found
+}
+
+// isInvisible reports whether r renders as nothing: a format character other
+// than a prepended concatenation mark, a variation selector, or another
+// default ignorable code point such as the Hangul fillers.
+func isInvisible(r rune) bool {
+	if unicode.Is(unicode.Prepended_Concatenation_Mark, r) {
+		return false
+	}
+
+	return unicode.In(r, unicode.Cf, unicode.Variation_Selector, unicode.Other_Default_Ignorable_Code_Point)
+}
+
+// joinsLetters reports whether the ZWJ or ZWNJ at offset sits between two
+// letters, where it shapes the text rather than hiding anything.
+func joinsLetters(name string, offset int) bool {
+	r, size := utf8.DecodeRuneInString(name[offset:])
+	if r != zeroWidthJoiner && r != zeroWidthNonJoiner {
+		return false
+	}
+
+	prev, _ := utf8.DecodeLastRuneInString(name[:offset])
+	next, _ := utf8.DecodeRuneInString(name[offset+size:])
+	isLetter := func(r rune) bool { return unicode.In(r, unicode.L, unicode.M) }
+
+	return isLetter(prev) && isLetter(next)
+}
+
+// describeInvisible names the kind of an invisible character.
+func describeInvisible(r rune) string {
+	switch {
+	case r == '\uFEFF':
+		return "byte order mark"
+	case unicode.Is(unicode.Bidi_Control, r):
+		return "bidi control"
+	case r >= '\u200B' && r <= '\u200D', r == '\u2060':
+		return "zero-width"
+	case unicode.Is(unicode.Variation_Selector, r):
+		return "variation selector"
+	case r >= 0xE0000 && r <= 0xE007F:
+		return "tag"
+	}
+
+	return "invisible"
+}
+
+// invisibleMessage lists the distinct invisible characters of a name.
+func invisibleMessage(found []invisibleCharacter) string {
+	var described []string
+	seen := map[rune]bool{}
+	for _, c := range found {
+		if seen[c.Rune] {
+			continue
+		}
+		seen[c.Rune] = true
+		described = append(described, fmt.Sprintf("U+%04X (%s)", c.Rune, describeInvisible(c.Rune)))
+	}
+
+	return "Name contains invisible characters: " + strings.Join(described, ", ")
+}
+
+// removeInvisible returns name without the given invisible characters.
+func removeInvisible(name string, found []invisibleCharacter) string {
+	var b strings.Builder
+	last := 0
+	for _, c := range found {
+		b.WriteString(name[last:c.Offset])
+		last = c.Offset + utf8.RuneLen(c.Rune)
+	}
+	b.WriteString(name[last:])
+
+	return b.String()
+}
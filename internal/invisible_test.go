@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"testing"
+)
+
+// TestInvisibleCharacters tests which code points are reported as invisible
+func TestInvisibleCharacters(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []rune
+		visible  string
+	}{
+		{"🚀 Deploy", nil, "🚀 Deploy"},
+		{"\u200b🚀 Deploy", []rune{0x200B}, "🚀 Deploy"},
+		{"\ufeff🚀 Deploy", []rune{0xFEFF}, "🚀 Deploy"},
+		{"\u202e🚀 yolpeD\u202c", []rune{0x202E, 0x202C}, "🚀 yolpeD"},
+		{"🚀 Dep\u2060loy", []rune{0x2060}, "🚀 Deploy"},
+		{"🚀 Deploy\u3164", []rune{0x3164}, "🚀 Deploy"},
+		{"🚀\u200d Deploy", []rune{0x200D}, "🚀 Deploy"},
+		{"🧑\u200d💻 Develop", nil, "🧑\u200d💻 Develop"},
+		{"❤️ Sponsor", nil, "❤️ Sponsor"},
+		{"🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f Release", nil, "🏴\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f Release"},
+		{"🚀 می\u200cخواهم", nil, "🚀 می\u200cخواهم"},
+		{"🚀 Deploy\u200c", []rune{0x200C}, "🚀 Deploy"},
+		{"🚀 Sum \u0600", nil, "🚀 Sum \u0600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.visible, func(t *testing.T) {
+			found := invisibleCharacters(tt.name)
+			if len(found) != len(tt.expected) {
+				t.Fatalf("invisibleCharacters(%q) = %+v, want %U", tt.name, found, tt.expected)
+			}
+			for i, c := range found {
+				if c.Rune != tt.expected[i] {
+					t.Errorf("invisibleCharacters(%q)[%d] = %U, want %U", tt.name, i, c.Rune, tt.expected[i])
+				}
+			}
+			if got := removeInvisible(tt.name, found); got != tt.visible {
+				t.Errorf("removeInvisible(%q) = %q, want %q", tt.name, got, tt.visible)
+			}
+		})
+	}
+}
+
+// TestLintWorkflowReport_Invisible tests that invisible characters are reported instead of a missing emoji
+func TestLintWorkflowReport_Invisible(t *testing.T) {
+	node, err := ParseYAML("testdata/invisible_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	violations, err := LintWorkflow(node)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	expected := []string{
+		"Name contains invisible characters: U+200B (zero-width)",
+		"Name contains invisible characters: U+FEFF (byte order mark)",
+		"Name contains invisible characters: U+202E (bidi control), U+202C (bidi control)",
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %+v", len(expected), len(violations), violations)
+	}
+	for i, v := range violations {
+		if v.Msg != expected[i] || v.Rule != RuleInvisible {
+			t.Errorf("violation %d = %q (%s), want %q (%s)", i, v.Msg, v.Rule, expected[i], RuleInvisible)
+		}
+	}
+}
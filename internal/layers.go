@@ -0,0 +1,584 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix starts every environment variable that sets a config value.
+	// Nested keys are separated by a double underscore, for example
+	// EMOJIGATE_RULES__REQUIRE_EMOJI=warning.
+	EnvPrefix = "EMOJIGATE_"
+	// EnvConfigFile names the config file instead of a config value.
+	EnvConfigFile = EnvPrefix + "CONFIG"
+
+	envKeySeparator = "__"
+	sourceDefault   = "default"
+)
+
+type configLayer struct {
+	source string
+	values map[string]any
+	// appendLists concatenates lists with the ones of earlier layers instead
+	// of replacing them, so a config file only records its additions.
+	appendLists bool
+}
+
+type configOverride struct {
+	files []string
+	layer configLayer
+}
+
+// ConfigLayers holds the config values of every source. Values are resolved
+// in order of precedence, later sources winning:
+// built-in defaults < extended presets and files < config file < overrides
+// matching the workflow < EMOJIGATE_* environment variables < command line flags.
+type ConfigLayers struct {
+	file      []configLayer
+	overrides []configOverride
+	top       []configLayer
+}
+
+// NewConfigLayers returns layers holding only the built-in defaults.
+func NewConfigLayers() *ConfigLayers {
+	return &ConfigLayers{}
+}
+
+// AddFile adds a config file, preceded by every preset and file it extends.
+// Its `overrides:` entries only apply to the workflows matching their
+// `files:` patterns.
+func (l *ConfigLayers) AddFile(file string) error {
+	return l.addFile(file, nil)
+}
+
+func (l *ConfigLayers) addFile(file string, chain []string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return l.addConfig(file, data, filepath.Dir(file), chain)
+}
+
+func (l *ConfigLayers) addPreset(name string, chain []string) error {
+	data, ok := readPreset(name)
+	if !ok {
+		return fmt.Errorf("unknown preset %q (expected one of: %s)", name, strings.Join(Presets(), ", "))
+	}
+
+	return l.addConfig("preset "+name, data, "", chain)
+}
+
+// addConfig adds the layers of a config read from source. Files it extends
+// are resolved relative to dir.
+func (l *ConfigLayers) addConfig(source string, data []byte, dir string, chain []string) error {
+	if contains(chain, source) {
+		return fmt.Errorf("config extends itself: %s", strings.Join(append(chain, source), " → "))
+	}
+	chain = append(chain, source)
+
+	problems, err := ValidateConfig(data)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return &InvalidConfigError{Source: source, Problems: problems}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	values := map[string]any{}
+	if len(root.Content) > 0 {
+		values, _ = decodeValue(configSchema(), root.Content[0]).(map[string]any)
+	}
+
+	extends, _ := values["extends"].([]any)
+	delete(values, "extends")
+	for _, e := range extends {
+		ref := fmt.Sprint(e)
+		if isPresetRef(ref) {
+			err = l.addPreset(ref, chain)
+		} else {
+			if !filepath.IsAbs(ref) {
+				ref = filepath.Join(dir, ref)
+			}
+			err = l.addFile(ref, chain)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	overrides, _ := values["overrides"].([]any)
+	delete(values, "overrides")
+	l.file = append(l.file, configLayer{source: source, values: values, appendLists: true})
+
+	for i, o := range overrides {
+		override, _ := o.(map[string]any)
+		files, _ := override["files"].([]any)
+		delete(override, "files")
+
+		entry := configOverride{layer: configLayer{
+			source:      fmt.Sprintf("%s (overrides[%d])", source, i),
+			values:      override,
+			appendLists: true,
+		}}
+		for _, f := range files {
+			entry.files = append(entry.files, fmt.Sprint(f))
+		}
+		l.overrides = append(l.overrides, entry)
+	}
+
+	return nil
+}
+
+// AddEnv adds every EMOJIGATE_* variable of environ, in KEY=value form.
+func (l *ConfigLayers) AddEnv(environ []string) error {
+	invalid := &InvalidConfigError{Source: "environment"}
+
+	sort.Strings(environ)
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || name == EnvConfigFile {
+			continue
+		}
+
+		key := strings.Split(strings.TrimPrefix(name, EnvPrefix), envKeySeparator)
+		layer, err := parseSetting("env "+name, key, value)
+		if err != nil {
+			invalid.Problems = append(invalid.Problems, ConfigProblem{Msg: fmt.Sprintf("%s: %v", name, err)})
+			continue
+		}
+		l.top = append(l.top, layer)
+	}
+
+	if len(invalid.Problems) > 0 {
+		return invalid
+	}
+
+	return nil
+}
+
+// AddFlag adds a `key=value` setting given on the command line, where key is
+// a dotted config key such as rules.require-emoji.
+func (l *ConfigLayers) AddFlag(setting string) error {
+	key, value, ok := strings.Cut(setting, "=")
+	if !ok {
+		return fmt.Errorf("invalid setting %q: expected key=value", setting)
+	}
+
+	source := "flag --set " + setting
+	layer, err := parseSetting(source, strings.Split(key, "."), value)
+	if err != nil {
+		return &InvalidConfigError{Source: source, Problems: []ConfigProblem{{Msg: err.Error()}}}
+	}
+	l.top = append(l.top, layer)
+
+	return nil
+}
+
+// AddLevels enables linting of exactly the given levels (workflow, job or
+// step), as set with the --levels flag.
+func (l *ConfigLayers) AddLevels(levels []string) error {
+	source := "flag --levels " + strings.Join(levels, ",")
+	levelNames := []string{"workflow", "job", "step"}
+
+	for _, level := range levels {
+		if !contains(levelNames, level) {
+			return &InvalidConfigError{Source: source, Problems: []ConfigProblem{{
+				Msg: fmt.Sprintf("unknown level %q (expected one of: %s)", level, strings.Join(levelNames, ", ")),
+			}}}
+		}
+	}
+
+	values := map[string]any{}
+	for _, level := range levelNames {
+		values[level] = map[string]any{"enabled": contains(levels, level)}
+	}
+	l.top = append(l.top, configLayer{source: source, values: map[string]any{"levels": values}})
+
+	return nil
+}
+
+// parseSetting parses value as YAML, places it at the config key matching
+// segments and validates it against the schema.
+func parseSetting(source string, segments []string, value string) (configLayer, error) {
+	key, err := resolveKey(configSchema(), segments)
+	if err != nil {
+		return configLayer{}, err
+	}
+
+	var valueNode yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &valueNode); err != nil {
+		return configLayer{}, fmt.Errorf("invalid value %q: %w", value, err)
+	}
+	var parsed any
+	if len(valueNode.Content) > 0 {
+		parsed = decodeValue(schemaAt(configSchema(), key), valueNode.Content[0])
+	}
+
+	values := map[string]any{}
+	current := values
+	for _, segment := range key[:len(key)-1] {
+		next := map[string]any{}
+		current[segment] = next
+		current = next
+	}
+	current[key[len(key)-1]] = parsed
+
+	var node yaml.Node
+	if err := node.Encode(values); err != nil {
+		return configLayer{}, fmt.Errorf("invalid value %q: %w", value, err)
+	}
+
+	var problems []ConfigProblem
+	validateNode(configSchema(), &node, "", &problems)
+	if len(problems) > 0 {
+		return configLayer{}, fmt.Errorf("%s", problems[0].Msg)
+	}
+
+	return configLayer{source: source, values: values}, nil
+}
+
+// decodeValue converts a YAML node to maps, lists and scalars. Scalars of
+// string settings keep their text as written, so `maxVersion: 13.0` stays
+// "13.0" rather than becoming the number 13.
+func decodeValue(schema *schemaNode, node *yaml.Node) any {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		values := map[string]any{}
+		for i := 0; i+1 < len(node.Content); i += yamlKeyValuePairSize {
+			key := node.Content[i].Value
+			values[key] = decodeValue(schemaAt(schema, []string{key}), node.Content[i+1])
+		}
+		return values
+	case yaml.ScalarNode:
+		if schema != nil && schema.Single {
+			return []any{decodeValue(schema.Items, node)}
+		}
+	case yaml.SequenceNode:
+		var items *schemaNode
+		if schema != nil {
+			items = schema.Items
+		}
+		values := []any{}
+		for _, item := range node.Content {
+			values = append(values, decodeValue(items, item))
+		}
+		return values
+	}
+
+	if schema != nil && schema.Type == "string" && node.Tag != "!!null" {
+		return node.Value
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+
+	return value
+}
+
+// schemaAt returns the schema of a key below schema, or nil if it is unknown.
+func schemaAt(schema *schemaNode, key []string) *schemaNode {
+	for _, segment := range key {
+		switch {
+		case schema == nil:
+			return nil
+		case schema.Properties != nil:
+			schema = schema.Properties[segment]
+		default:
+			schema = schema.Values
+		}
+	}
+
+	return schema
+}
+
+// resolveKey matches key segments against the schema. Matching ignores case,
+// dashes and underscores, so RULES__REQUIRE_EMOJI finds rules.require-emoji.
+func resolveKey(schema *schemaNode, segments []string) ([]string, error) {
+	var key []string
+
+	for _, segment := range segments {
+		if schema == nil || schema.Type != "object" {
+			return nil, fmt.Errorf("unknown key %q", strings.Join(append(key, segment), "."))
+		}
+
+		switch {
+		case schema.Properties != nil:
+			name, ok := matchKey(sortedPropertyNames(schema), segment)
+			if !ok || name == "overrides" || name == "extends" {
+				return nil, fmt.Errorf("unknown key %q", strings.Join(append(key, segment), "."))
+			}
+			key = append(key, name)
+			schema = schema.Properties[name]
+		case schema.Keys != nil:
+			name, ok := matchKey(schema.Keys, segment)
+			if !ok {
+				return nil, fmt.Errorf("unknown %s %q", schema.KeyKind, segment)
+			}
+			key = append(key, name)
+			schema = schema.Values
+		default:
+			key = append(key, segment)
+			schema = schema.Values
+		}
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+
+	return key, nil
+}
+
+func matchKey(names []string, segment string) (string, bool) {
+	for _, name := range names {
+		if normalizeKey(name) == normalizeKey(segment) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(key))
+}
+
+func sortedPropertyNames(schema *schemaNode) []string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ResolvedConfig is the effective config for one workflow file together with
+// the source of every value.
+type ResolvedConfig struct {
+	Config  *Config
+	Sources map[string]string
+	values  map[string]any
+}
+
+// Setting is a single resolved config value.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// Resolve merges every layer that applies to file. Pass an empty file to
+// resolve the repository-wide config without overrides.
+func (l *ConfigLayers) Resolve(file string) (*ResolvedConfig, error) {
+	resolved := &ResolvedConfig{values: map[string]any{}, Sources: map[string]string{}}
+
+	layers := []configLayer{{source: sourceDefault, values: defaultValues()}}
+	layers = append(layers, l.file...)
+	for _, o := range l.overrides {
+		if file != "" && matchesAny(o.files, file) {
+			layers = append(layers, o.layer)
+		}
+	}
+	layers = append(layers, l.top...)
+
+	for _, layer := range layers {
+		mergeValues(resolved.values, layer, layer.values, resolved.Sources, "")
+	}
+
+	data, err := yaml.Marshal(resolved.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+	if err := cfg.compile(); err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+	resolved.Config = cfg
+
+	return resolved, nil
+}
+
+// Settings returns every resolved value as a dotted key, sorted by key.
+func (r *ResolvedConfig) Settings() []Setting {
+	var settings []Setting
+	flattenValues(r.values, "", func(key string, value any) {
+		settings = append(settings, Setting{Key: key, Value: formatValue(value), Source: r.Sources[key]})
+	})
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+
+	return settings
+}
+
+// defaultValues returns the built-in config as a value tree, so that every
+// setting shows up with a source even when no config sets it.
+func defaultValues() map[string]any {
+	rules := map[string]any{}
+	for _, rule := range Rules {
+		rules[rule.ID] = string(rule.Default)
+	}
+
+	levels := map[string]any{}
+	for _, level := range []string{"workflow", "job", "step"} {
+		levels[level] = map[string]any{"enabled": true, "placement": string(PlacementPrefix), "separator": DefaultSeparator, "maxEmoji": 0, "semantics": []any{}}
+	}
+	for _, level := range []string{"job", "step"} {
+		levels[level].(map[string]any)["ignore"] = []any{}
+		levels[level].(map[string]any)["maxRepeat"] = 1
+		levels[level].(map[string]any)["allowRepeat"] = []any{}
+	}
+
+	return map[string]any{
+		"ignore": []any{},
+		"rules":  rules,
+		"levels": levels,
+		"emoji":  map[string]any{"allow": []any{}, "deny": []any{}, "maxVersion": "", "skinTone": string(SkinToneAny), "genderNeutral": false, "actions": map[string]any{}, "jobs": map[string]any{}},
+	}
+}
+
+// mergeValues copies src of layer into dst. Maps are merged key by key. Lists
+// of layers with appendLists are concatenated without duplicates, any other
+// value replaces the previous one. The source of every changed leaf is recorded.
+func mergeValues(dst map[string]any, layer configLayer, src map[string]any, sources map[string]string, prefix string) {
+	for key, value := range src {
+		path := joinKey(prefix, key)
+
+		if m, ok := value.(map[string]any); ok {
+			existing, ok := dst[key].(map[string]any)
+			if !ok {
+				existing = map[string]any{}
+				dst[key] = existing
+				clearSources(sources, path)
+				// An empty map, such as emoji.actions, is a leaf of its own
+				if len(m) == 0 {
+					sources[path] = layer.source
+				}
+			}
+			mergeValues(existing, layer, m, sources, path)
+			continue
+		}
+
+		if list, ok := value.([]any); ok && layer.appendLists {
+			if existing, ok := dst[key].([]any); ok && len(existing) > 0 {
+				if merged := appendUnique(existing, list); len(merged) > len(existing) {
+					dst[key] = merged
+					sources[path] = joinSources(sources[path], layer.source)
+				}
+				continue
+			}
+		}
+
+		clearSources(sources, path)
+		dst[key] = value
+		sources[path] = layer.source
+	}
+}
+
+func appendUnique(list, items []any) []any {
+	merged := append([]any{}, list...)
+	seen := map[string]bool{}
+	for _, item := range list {
+		seen[fmt.Sprint(item)] = true
+	}
+	for _, item := range items {
+		if !seen[fmt.Sprint(item)] {
+			seen[fmt.Sprint(item)] = true
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
+
+func joinSources(existing, source string) string {
+	if existing == "" || existing == sourceDefault {
+		return source
+	}
+
+	return existing + ", " + source
+}
+
+func clearSources(sources map[string]string, path string) {
+	for key := range sources {
+		if key == path || strings.HasPrefix(key, path+".") {
+			delete(sources, key)
+		}
+	}
+}
+
+func flattenValues(values map[string]any, prefix string, fn func(key string, value any)) {
+	for key, value := range values {
+		path := joinKey(prefix, key)
+		if m, ok := value.(map[string]any); ok && len(m) > 0 {
+			flattenValues(m, path, fn)
+			continue
+		}
+		fn(path, value)
+	}
+}
+
+func formatValue(value any) string {
+	if s, ok := value.(string); ok {
+		if s == "" || strings.TrimSpace(s) != s {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err == nil && len(node.Content) > 0 {
+		setFlowStyle(node.Content[0])
+		if flow, err := yaml.Marshal(node.Content[0]); err == nil {
+			data = flow
+		}
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func setFlowStyle(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		node.Style |= yaml.FlowStyle
+	}
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
+
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+	}
+
+	return false
+}
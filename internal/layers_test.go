@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestConfigLayers_Precedence tests that each source overrides the ones before it
+func TestConfigLayers_Precedence(t *testing.T) {
+	tests := []struct {
+		name            string
+		file            string
+		env             []string
+		flags           []string
+		expectedRequire Severity
+		expectedSource  string
+	}{
+		{
+			name:            "default",
+			file:            ".github/workflows/ci.yml",
+			expectedRequire: SeverityError,
+			expectedSource:  "default",
+		},
+		{
+			name:            "override matching the workflow",
+			file:            ".github/workflows/legacy-build.yml",
+			expectedRequire: SeverityWarning,
+			expectedSource:  "testdata/config_overrides.yml (overrides[0])",
+		},
+		{
+			name:            "environment beats override",
+			file:            ".github/workflows/legacy-build.yml",
+			env:             []string{"EMOJIGATE_RULES__REQUIRE_EMOJI=off", "PATH=/usr/bin"},
+			expectedRequire: SeverityOff,
+			expectedSource:  "env EMOJIGATE_RULES__REQUIRE_EMOJI",
+		},
+		{
+			name:            "flag beats environment",
+			file:            ".github/workflows/ci.yml",
+			env:             []string{"EMOJIGATE_RULES__REQUIRE_EMOJI=off"},
+			flags:           []string{"rules.require-emoji=warning"},
+			expectedRequire: SeverityWarning,
+			expectedSource:  "flag --set rules.require-emoji=warning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := NewConfigLayers()
+			if err := layers.AddFile("testdata/config_overrides.yml"); err != nil {
+				t.Fatalf("AddFile() failed: %v", err)
+			}
+			if err := layers.AddEnv(tt.env); err != nil {
+				t.Fatalf("AddEnv() failed: %v", err)
+			}
+			for _, flag := range tt.flags {
+				if err := layers.AddFlag(flag); err != nil {
+					t.Fatalf("AddFlag() failed: %v", err)
+				}
+			}
+
+			resolved, err := layers.Resolve(tt.file)
+			if err != nil {
+				t.Fatalf("Resolve() failed: %v", err)
+			}
+
+			if got := resolved.Config.Severity(RuleRequireEmoji); got != tt.expectedRequire {
+				t.Errorf("Severity(%s) = %s, want %s", RuleRequireEmoji, got, tt.expectedRequire)
+			}
+			if got := resolved.Sources["rules.require-emoji"]; got != tt.expectedSource {
+				t.Errorf("source of rules.require-emoji = %q, want %q", got, tt.expectedSource)
+			}
+
+			// Values from the config file are untouched by the other layers
+			if got := resolved.Config.Severity(RuleMissingName); got != SeverityWarning {
+				t.Errorf("Severity(%s) = %s, want %s", RuleMissingName, got, SeverityWarning)
+			}
+		})
+	}
+}
+
+// TestConfigLayers_Settings tests the flattened settings shown by `config print`
+func TestConfigLayers_Settings(t *testing.T) {
+	layers := NewConfigLayers()
+	if err := layers.AddFile("testdata/config_overrides.yml"); err != nil {
+		t.Fatalf("AddFile() failed: %v", err)
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	expected := []Setting{
+		{Key: "emoji.actions", Value: "{}", Source: "default"},
+		{Key: "emoji.allow", Value: "[]", Source: "default"},
+		{Key: "emoji.deny", Value: "[]", Source: "default"},
+		{Key: "emoji.genderNeutral", Value: "false", Source: "default"},
+		{Key: "emoji.jobs", Value: "{}", Source: "default"},
+		{Key: "emoji.maxVersion", Value: `""`, Source: "default"},
+		{Key: "emoji.skinTone", Value: "any", Source: "default"},
+		{Key: "ignore", Value: "[.github/workflows/skip-*.yml]", Source: "testdata/config_overrides.yml"},
+		{Key: "levels.job.allowRepeat", Value: "[]", Source: "default"},
+		{Key: "levels.job.enabled", Value: "true", Source: "default"},
+		{Key: "levels.job.ignore", Value: "[]", Source: "default"},
+		{Key: "levels.job.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.job.maxRepeat", Value: "1", Source: "default"},
+		{Key: "levels.job.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.job.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.job.separator", Value: `" "`, Source: "default"},
+		{Key: "levels.step.allowRepeat", Value: "[]", Source: "default"},
+		{Key: "levels.step.enabled", Value: "true", Source: "default"},
+		{Key: "levels.step.ignore", Value: "[]", Source: "default"},
+		{Key: "levels.step.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.step.maxRepeat", Value: "1", Source: "default"},
+		{Key: "levels.step.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.step.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.step.separator", Value: `" "`, Source: "default"},
+		{Key: "levels.workflow.enabled", Value: "true", Source: "default"},
+		{Key: "levels.workflow.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.workflow.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.workflow.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.workflow.separator", Value: `" "`, Source: "default"},
+		{Key: "rules.action-emoji", Value: "warning", Source: "default"},
+		{Key: "rules.allowed-emoji", Value: "error", Source: "default"},
+		{Key: "rules.denied-emoji", Value: "error", Source: "default"},
+		{Key: "rules.duplicate-emoji", Value: "off", Source: "default"},
+		{Key: "rules.duplicate-name", Value: "warning", Source: "default"},
+		{Key: "rules.emoji-count", Value: "error", Source: "default"},
+		{Key: "rules.emoji-meaning", Value: "warning", Source: "default"},
+		{Key: "rules.emoji-version", Value: "error", Source: "default"},
+		{Key: "rules.inclusive-emoji", Value: "error", Source: "default"},
+		{Key: "rules.invisible-characters", Value: "error", Source: "default"},
+		{Key: "rules.job-emoji", Value: "warning", Source: "default"},
+		{Key: "rules.missing-name", Value: "warning", Source: "testdata/config_overrides.yml"},
+		{Key: "rules.require-emoji", Value: "error", Source: "default"},
+		{Key: "rules.semantic-emoji", Value: "error", Source: "default"},
+		{Key: "rules.separator", Value: "error", Source: "default"},
+		{Key: "rules.shortcode", Value: "error", Source: "default"},
+		{Key: "rules.text-presentation", Value: "warning", Source: "default"},
+	}
+
+	settings := resolved.Settings()
+	if len(settings) != len(expected) {
+		t.Fatalf("Settings() returned %d settings, want %d: %+v", len(settings), len(expected), settings)
+	}
+	for i, s := range settings {
+		if s != expected[i] {
+			t.Errorf("setting %d = %+v, want %+v", i, s, expected[i])
+		}
+	}
+}
+
+// TestConfigLayers_InvalidSettings tests that unknown keys and bad values from env and flags are rejected
+func TestConfigLayers_InvalidSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   []string
+		flag  string
+		error string
+	}{
+		{
+			name:  "unknown env key",
+			env:   []string{"EMOJIGATE_RULEZ=off"},
+			error: `invalid config environment: EMOJIGATE_RULEZ: unknown key "RULEZ"`,
+		},
+		{
+			name:  "unknown rule ID in env",
+			env:   []string{"EMOJIGATE_RULES__NO_SUCH_RULE=off"},
+			error: `invalid config environment: EMOJIGATE_RULES__NO_SUCH_RULE: unknown rule ID "NO_SUCH_RULE"`,
+		},
+		{
+			name:  "invalid severity in flag",
+			flag:  "rules.require-emoji=fatal",
+			error: `invalid config flag --set rules.require-emoji=fatal: invalid value "fatal" for 'rules.require-emoji' (expected one of: error, warning, off)`,
+		},
+		{
+			name:  "invalid regular expression in flag",
+			flag:  "levels.step.ignore=[\"(\"]",
+			error: `invalid config flag --set levels.step.ignore=["("]: invalid regular expression "(" in 'levels.step.ignore[0]'`,
+		},
+		{
+			name:  "overrides cannot be set from flags",
+			flag:  "overrides=[]",
+			error: `invalid config flag --set overrides=[]: unknown key "overrides"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := NewConfigLayers()
+
+			err := layers.AddEnv(tt.env)
+			if tt.flag != "" {
+				err = layers.AddFlag(tt.flag)
+			}
+
+			var invalid *InvalidConfigError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("error = %v, want *InvalidConfigError", err)
+			}
+			if err.Error() != tt.error {
+				t.Errorf("error = %q, want %q", err.Error(), tt.error)
+			}
+		})
+	}
+}
+
+// TestConfigLayers_Extends tests that presets and extended files are merged below the config file
+func TestConfigLayers_Extends(t *testing.T) {
+	layers := NewConfigLayers()
+	if err := layers.AddFile("testdata/config_extends.yml"); err != nil {
+		t.Fatalf("AddFile() failed: %v", err)
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	expected := []Setting{
+		{Key: "emoji.actions", Value: "{}", Source: "default"},
+		{Key: "emoji.allow", Value: "[]", Source: "default"},
+		{Key: "emoji.deny", Value: "[]", Source: "default"},
+		{Key: "emoji.genderNeutral", Value: "false", Source: "default"},
+		{Key: "emoji.jobs", Value: "{}", Source: "default"},
+		{Key: "emoji.maxVersion", Value: `""`, Source: "default"},
+		{Key: "emoji.skinTone", Value: "any", Source: "default"},
+		{Key: "ignore", Value: "[.github/workflows/generated-*.yml, .github/workflows/skip-*.yml, .github/workflows/legacy-*.yml]", Source: "testdata/shared/org.yml, testdata/config_extends.yml"},
+		{Key: "levels.job.allowRepeat", Value: "[]", Source: "default"},
+		{Key: "levels.job.enabled", Value: "true", Source: "default"},
+		{Key: "levels.job.ignore", Value: "[]", Source: "default"},
+		{Key: "levels.job.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.job.maxRepeat", Value: "1", Source: "default"},
+		{Key: "levels.job.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.job.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.job.separator", Value: `" "`, Source: "default"},
+		{Key: "levels.step.allowRepeat", Value: "[]", Source: "default"},
+		{Key: "levels.step.enabled", Value: "false", Source: "preset steps-optional"},
+		{Key: "levels.step.ignore", Value: "[]", Source: "default"},
+		{Key: "levels.step.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.step.maxRepeat", Value: "1", Source: "default"},
+		{Key: "levels.step.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.step.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.step.separator", Value: `" "`, Source: "default"},
+		{Key: "levels.workflow.enabled", Value: "true", Source: "default"},
+		{Key: "levels.workflow.maxEmoji", Value: "0", Source: "default"},
+		{Key: "levels.workflow.placement", Value: "prefix", Source: "default"},
+		{Key: "levels.workflow.semantics", Value: "[]", Source: "default"},
+		{Key: "levels.workflow.separator", Value: `" "`, Source: "default"},
+		{Key: "rules.action-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.allowed-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.denied-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.duplicate-emoji", Value: "off", Source: "default"},
+		{Key: "rules.duplicate-name", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.emoji-count", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.emoji-meaning", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.emoji-version", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.inclusive-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.invisible-characters", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.job-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.missing-name", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.require-emoji", Value: "error", Source: "testdata/config_extends.yml"},
+		{Key: "rules.semantic-emoji", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.separator", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.shortcode", Value: "warning", Source: "preset relaxed"},
+		{Key: "rules.text-presentation", Value: "warning", Source: "preset relaxed"},
+	}
+
+	settings := resolved.Settings()
+	if len(settings) != len(expected) {
+		t.Fatalf("Settings() returned %d settings, want %d: %+v", len(settings), len(expected), settings)
+	}
+	for i, s := range settings {
+		if s != expected[i] {
+			t.Errorf("setting %d = %+v, want %+v", i, s, expected[i])
+		}
+	}
+}
+
+// TestConfigLayers_ExtendsErrors tests that broken extends chains are rejected
+func TestConfigLayers_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		error string
+	}{
+		{
+			name:  "cycle",
+			file:  "testdata/config_extends_cycle.yml",
+			error: "config extends itself: testdata/config_extends_cycle.yml → testdata/config_extends_cycle.yml",
+		},
+		{
+			name:  "missing file",
+			file:  "testdata/non_existent_config.yml",
+			error: "failed to read config: open testdata/non_existent_config.yml: no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewConfigLayers().AddFile(tt.file)
+			if err == nil || err.Error() != tt.error {
+				t.Errorf("AddFile() error = %v, want %q", err, tt.error)
+			}
+		})
+	}
+}
+
+// TestConfigLayers_AddLevels tests that --levels enables exactly the listed levels
+func TestConfigLayers_AddLevels(t *testing.T) {
+	layers := NewConfigLayers()
+	if err := layers.AddLevels([]string{"workflow", "job"}); err != nil {
+		t.Fatalf("AddLevels() failed: %v", err)
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	expected := map[GithubActionType]bool{Workflow: true, Job: true, Step: false}
+	for level, enabled := range expected {
+		if got := resolved.Config.Level(level).Enabled; got != enabled {
+			t.Errorf("Level(%s).Enabled = %v, want %v", level, got, enabled)
+		}
+	}
+	if got := resolved.Sources["levels.step.enabled"]; got != "flag --levels workflow,job" {
+		t.Errorf("source of levels.step.enabled = %q", got)
+	}
+
+	err = NewConfigLayers().AddLevels([]string{"steps"})
+	if err == nil || err.Error() != `invalid config flag --levels steps: unknown level "steps" (expected one of: workflow, job, step)` {
+		t.Errorf("AddLevels(steps) error = %v", err)
+	}
+}
+
+// TestConfigLayers_StringValues tests that string settings keep their text when written as numbers
+func TestConfigLayers_StringValues(t *testing.T) {
+	layers := NewConfigLayers()
+	if err := layers.AddFlag("emoji.maxVersion=13.0"); err != nil {
+		t.Fatalf("AddFlag() failed: %v", err)
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	if got := resolved.Config.Emoji.MaxVersion; got != "13.0" {
+		t.Errorf("Emoji.MaxVersion = %q, want %q", got, "13.0")
+	}
+}
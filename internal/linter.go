@@ -2,6 +2,9 @@ package internal
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,10 +23,82 @@ type Violation struct {
 	Type       GithubActionType
 	Identifier string
 	Msg        string
+	Rule       string
+	Severity   Severity
+	// Line and Column locate the name, or the entity missing one.
+	Line   int
+	Column int
+	// Fix resolves the violation when set, see ApplyFixes.
+	Fix *Fix `json:",omitempty"`
+	// Explanation describes the characters behind an emoji violation and why
+	// they were rejected, such as "\"★\" is U+2605 (black star): not an
+	// emoji". It is shown with --verbose.
+	Explanation string `json:",omitempty"`
 }
 
+// Skipped is a job or step left out of linting by an ignore pattern.
+type Skipped struct {
+	Type       GithubActionType
+	Identifier string
+	Pattern    string
+}
+
+// Report is the result of linting a single workflow file.
+type Report struct {
+	Violations []Violation
+	Skipped    []Skipped
+	Compliance Compliance
+	// counted holds where the names counted in Compliance are, so that
+	// cross-file errors only count against those.
+	counted map[position]bool
+}
+
+// position locates a name in a workflow file.
+type position struct {
+	Line, Column int
+}
+
+// AddCrossFile adds the violations found across files, see Consistency, and
+// stops counting a name as passing once one of them is an error. Names the
+// linter left uncounted, such as the steps of a job without a name, keep
+// the compliance as it is.
+func (r *Report) AddCrossFile(violations []Violation) {
+	for _, v := range violations {
+		counted := r.counted[position{v.Line, v.Column}]
+		if v.Severity == SeverityError && counted && !r.failed(v.Line, v.Column) && r.Compliance.Passing > 0 {
+			r.Compliance.Passing--
+		}
+		r.Violations = append(r.Violations, v)
+	}
+}
+
+// failed reports whether the name at line and column has an error.
+func (r *Report) failed(line, column int) bool {
+	for _, v := range r.Violations {
+		if v.Severity == SeverityError && v.Line == line && v.Column == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LintWorkflow lints a workflow with the default config.
 func LintWorkflow(root *yaml.Node) ([]Violation, error) {
-	violations := []Violation{}
+	report, err := LintWorkflowReport(root, DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	return report.Violations, nil
+}
+
+// LintWorkflowReport lints a workflow with the given config and additionally
+// counts how many of its names pass every check.
+func LintWorkflowReport(root *yaml.Node, cfg *Config) (*Report, error) {
+	report := &Report{Violations: []Violation{}, counted: map[position]bool{}}
+	l := &linter{cfg: cfg, report: report, siblings: map[GithubActionType]map[string][]int{Job: {}}}
+	l.duplicates = duplicateNames(root, cfg)
 
 	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
 		return nil, fmt.Errorf("invalid workflow: expected document node")
@@ -34,38 +109,46 @@ func LintWorkflow(root *yaml.Node) ([]Violation, error) {
 		return nil, fmt.Errorf("invalid workflow: root must be a mapping")
 	}
 
-	lintWorkflowName(workflowRoot, &violations)
+	if l.cfg.Level(Workflow).Enabled {
+		l.lintWorkflowName(workflowRoot)
+	}
 
 	jobsNode, err := findJobsNode(workflowRoot)
 	if err != nil {
 		return nil, fmt.Errorf("jobs section not found in workflow")
 	}
 
-	if err := lintJobs(jobsNode, &violations); err != nil {
+	if err := l.lintJobs(jobsNode); err != nil {
 		return nil, err
 	}
 
-	return violations, nil
+	return report, nil
+}
+
+// linter holds the state of a single LintWorkflowReport run.
+type linter struct {
+	cfg    *Config
+	report *Report
+	// skinTone is the first skin tone used in the workflow and toneEmoji the
+	// emoji it was used in, for emoji.skinTone: consistent.
+	skinTone  rune
+	toneEmoji string
+	// siblings maps the emoji carried by the jobs of the workflow, and by
+	// the steps of the current job, to the lines of their names.
+	siblings map[GithubActionType]map[string][]int
+	// duplicates maps the name nodes of jobs and steps sharing their name
+	// with a sibling to the duplicate-name message, see duplicateNames.
+	duplicates map[*yaml.Node]string
 }
 
-func lintWorkflowName(workflowRoot *yaml.Node, out *[]Violation) {
+func (l *linter) lintWorkflowName(workflowRoot *yaml.Node) {
 	name, err := getName(workflowRoot)
 	if err != nil {
-		*out = append(*out, Violation{
-			Type:       Workflow,
-			Identifier: "workflow",
-			Msg:        "Missing display name. Please add a 'name:' field starting with an emoji.",
-		})
+		l.missingName(Workflow, "workflow", workflowRoot)
 		return
 	}
 
-	if !startsWithEmoji(name) {
-		*out = append(*out, Violation{
-			Type:       Workflow,
-			Identifier: name,
-			Msg:        "Name must start with an emoji. Example: '🚀 Deploy'",
-		})
-	}
+	l.checkName(Workflow, name.Value, name)
 }
 
 func findJobsNode(workflowRoot *yaml.Node) (*yaml.Node, error) {
@@ -81,34 +164,35 @@ func findJobsNode(workflowRoot *yaml.Node) (*yaml.Node, error) {
 	return nil, fmt.Errorf("jobs key not found")
 }
 
-func lintJobs(jobsNode *yaml.Node, out *[]Violation) error {
+func (l *linter) lintJobs(jobsNode *yaml.Node) error {
 	if len(jobsNode.Content)%yamlKeyValuePairSize != 0 {
 		return fmt.Errorf("jobs node content is not even. Every job must have configuration set")
 	}
 
 	for i := 0; i < len(jobsNode.Content); i += yamlKeyValuePairSize {
-		jobName := jobsNode.Content[i].Value
+		jobKey := jobsNode.Content[i]
+		jobName := jobKey.Value
 		jobConfig := jobsNode.Content[i+1]
 
-		name, err := getName(jobConfig)
-		if err != nil {
-			*out = append(*out, Violation{
-				Type:       Job,
-				Identifier: jobName,
-				Msg:        "Missing display name. Please add a 'name:' field starting with an emoji.",
-			})
+		if pattern, ok := l.cfg.Level(Job).IgnoredBy(jobName); ok {
+			l.report.Skipped = append(l.report.Skipped, Skipped{Type: Job, Identifier: jobName, Pattern: pattern})
 			continue
 		}
 
-		if !startsWithEmoji(name) {
-			*out = append(*out, Violation{
-				Type:       Job,
-				Identifier: jobName,
-				Msg:        "Name must start with an emoji. Example: '🚀 Deploy'",
-			})
+		if l.cfg.Level(Job).Enabled {
+			name, err := getName(jobConfig)
+			if err != nil {
+				l.missingName(Job, jobName, jobKey)
+				continue
+			}
+
+			l.checkName(Job, jobName, name)
 		}
 
-		if err := lintSteps(jobConfig, out); err != nil {
+		if !l.cfg.Level(Step).Enabled {
+			continue
+		}
+		if err := l.lintSteps(jobConfig); err != nil {
 			return err
 		}
 	}
@@ -125,7 +209,7 @@ func findStepsIndex(nodes []*yaml.Node) int {
 	return -1
 }
 
-func lintSteps(stepsNode *yaml.Node, out *[]Violation) error {
+func (l *linter) lintSteps(stepsNode *yaml.Node) error {
 	index := findStepsIndex(stepsNode.Content)
 	if index == -1 {
 		return nil
@@ -135,8 +219,9 @@ func lintSteps(stepsNode *yaml.Node, out *[]Violation) error {
 		return fmt.Errorf("steps node content is not even. Every step must have configuration set")
 	}
 
+	l.siblings[Step] = map[string][]int{}
 	for _, step := range stepsNode.Content[index+1].Content {
-		err := lintStep(step, out)
+		err := l.lintStep(step)
 		if err != nil {
 			return err
 		}
@@ -145,7 +230,7 @@ func lintSteps(stepsNode *yaml.Node, out *[]Violation) error {
 	return nil
 }
 
-func lintStep(stepNode *yaml.Node, out *[]Violation) error {
+func (l *linter) lintStep(stepNode *yaml.Node) error {
 	index, err := findNameIndex(stepNode.Content)
 	if err != nil {
 		return fmt.Errorf("step does not contain a name property")
@@ -154,17 +239,182 @@ func lintStep(stepNode *yaml.Node, out *[]Violation) error {
 	nameNode := stepNode.Content[index+1]
 
 	name := nameNode.Value
-	if !startsWithEmoji(name) {
-		*out = append(*out, Violation{
-			Msg:        "Name must start with an emoji. Example: '🚀 Deploy'",
-			Type:       Step,
-			Identifier: name,
-		})
+	if pattern, ok := l.cfg.Level(Step).IgnoredBy(name); ok {
+		l.report.Skipped = append(l.report.Skipped, Skipped{Type: Step, Identifier: name, Pattern: pattern})
+		return nil
 	}
 
+	l.checkName(Step, name, nameNode)
+
 	return nil
 }
 
+// missingName records a violation for an entity without a display name,
+// located at node.
+func (l *linter) missingName(t GithubActionType, identifier string, node *yaml.Node) {
+	l.report.Compliance.Names++
+	l.report.counted[position{node.Line, node.Column}] = true
+
+	failed := l.add(Violation{
+		Type:       t,
+		Identifier: identifier,
+		Msg:        "Missing display name. Please add a 'name:' field starting with an emoji.",
+		Rule:       RuleMissingName,
+		Line:       node.Line,
+		Column:     node.Column,
+	})
+	if !failed {
+		l.report.Compliance.Passing++
+	}
+}
+
+// checkName runs every name check on the scalar nameNode and counts the name
+// as passing when none of them report an error.
+func (l *linter) checkName(t GithubActionType, identifier string, nameNode *yaml.Node) {
+	l.report.Compliance.Names++
+	l.report.counted[position{nameNode.Line, nameNode.Column}] = true
+	failed := false
+	explain := func(rule, msg, explanation string, fix *Fix) {
+		failed = l.add(Violation{
+			Type:        t,
+			Identifier:  identifier,
+			Msg:         msg,
+			Rule:        rule,
+			Line:        nameNode.Line,
+			Column:      nameNode.Column,
+			Fix:         fix,
+			Explanation: explanation,
+		}) || failed
+	}
+	report := func(rule, msg string, fix *Fix) {
+		explain(rule, msg, "", fix)
+	}
+
+	name := nameNode.Value
+	if found := invisibleCharacters(name); len(found) > 0 {
+		// The remaining checks see the name as it is displayed
+		visible := removeInvisible(name, found)
+		first, last := found[0], found[len(found)-1]
+		end := last.Offset + utf8.RuneLen(last.Rune)
+		column := nameNode.Column + quoteWidth(nameNode) + utf8.RuneCountInString(name[:first.Offset])
+		report(RuleInvisible, invisibleMessage(found),
+			&Fix{Line: nameNode.Line, Column: column, Old: name[first.Offset:end], New: visible[first.Offset : len(visible)-(len(name)-end)]})
+		name = visible
+	}
+
+	level := l.cfg.Level(t)
+	placed := true
+	var emojis []Emoji
+	var explanations []string
+	slots := emojiSlots(name, level.Placement)
+	for _, slot := range slots {
+		if slot.Shortcode != "" {
+			// The shortcode stands for its emoji in the remaining checks
+			column := nameNode.Column + quoteWidth(nameNode) + utf8.RuneCountInString(name[:slot.Offset])
+			report(RuleShortcode, fmt.Sprintf("Shortcode %s is not rendered in Actions, use %s instead", slot.Shortcode, slot.Sequence),
+				&Fix{Line: nameNode.Line, Column: column, Old: slot.Shortcode, New: slot.Sequence})
+			slot.Emoji, slot.ok = LeadingEmoji(slot.Sequence)
+		}
+		if !slot.ok {
+			placed = false
+			if explanation := explainSlot(name, slot); explanation != "" {
+				explanations = append(explanations, explanation)
+			}
+			continue
+		}
+		if len(emojis) == 0 || emojis[0].Qualified != slot.Emoji.Qualified {
+			emojis = append(emojis, slot.Emoji)
+		}
+	}
+
+	if !placed {
+		var fix *Fix
+		if moved, ok := moveEmoji(name, level.Placement, level.Separator); ok {
+			fix = &Fix{Line: nameNode.Line, Column: nameNode.Column, Old: name, New: moved}
+		}
+		explain(RuleRequireEmoji, missingEmojiMessage(level.Placement), strings.Join(explanations, "; "), fix)
+	}
+
+	for _, slot := range slots {
+		if !slot.ok || slot.Shortcode != "" || !needsPresentationSelector(slot.Emoji) {
+			continue
+		}
+
+		column := nameNode.Column + quoteWidth(nameNode) + utf8.RuneCountInString(name[:slot.Offset])
+		explain(RuleTextStyle, fmt.Sprintf("Emoji %s renders as a text glyph without U+FE0F, use %s", slot.Emoji.Text, slot.Emoji.Qualified),
+			describeGrapheme(slot.Emoji.Text)+": text presentation by default", &Fix{Line: nameNode.Line, Column: column, Old: slot.Emoji.Text, New: slot.Emoji.Qualified})
+	}
+
+	for _, slot := range slots {
+		separator, offset, ok := slot.separator(name)
+		if !slot.ok || slot.Shortcode != "" || !ok || separator == level.Separator {
+			continue
+		}
+
+		old, fixed := slot.Emoji.Text+separator, slot.Emoji.Text+level.Separator
+		if slot.Side == sideEnd {
+			old, fixed = separator+slot.Emoji.Text, level.Separator+slot.Emoji.Text
+		}
+		column := nameNode.Column + quoteWidth(nameNode) + utf8.RuneCountInString(name[:offset])
+		report(RuleSeparator, fmt.Sprintf("Expected %s between %s and the text, found %s", describeSeparator(level.Separator), slot.Emoji.Text, describeSeparator(separator)),
+			&Fix{Line: nameNode.Line, Column: column, Old: old, New: fixed})
+	}
+
+	if count := countEmoji(name); level.MaxEmoji > 0 && count > level.MaxEmoji {
+		report(RuleEmojiCount, fmt.Sprintf("Name has %d emoji, more than levels.%s.maxEmoji %d", count, strings.ToLower(string(t)), level.MaxEmoji), nil)
+	}
+
+	for _, emoji := range emojis {
+		for _, v := range l.cfg.CheckEmoji(emoji) {
+			explain(v.Rule, v.Msg, v.Explanation, nil)
+		}
+	}
+
+	if rule, ok := level.SemanticRule(nameText(name, slots)); ok && len(emojis) > 0 && !slices.ContainsFunc(emojis, rule.Allows) {
+		report(RuleSemantic, fmt.Sprintf("Name matches %s and must use %s, not %s", rule.Match, rule.describeEmoji(), emojiTexts(emojis)), nil)
+	}
+
+	if msg, ok := l.duplicates[nameNode]; ok {
+		report(RuleDuplicateName, msg, nil)
+	}
+
+	if len(emojis) > 0 {
+		if msg, repeated := l.checkRepeat(t, level, emojis[0], nameNode.Line); repeated {
+			report(RuleDuplicateEmoji, msg, nil)
+		}
+	}
+
+	for _, slot := range slots {
+		if !slot.ok || slot.Shortcode != "" {
+			continue
+		}
+
+		column := nameNode.Column + quoteWidth(nameNode) + utf8.RuneCountInString(name[:slot.Offset])
+		for _, v := range l.checkInclusive(slot.Emoji) {
+			if v.Fix != nil {
+				v.Fix.Line, v.Fix.Column = nameNode.Line, column
+			}
+			report(v.Rule, v.Msg, v.Fix)
+		}
+	}
+
+	if !failed {
+		l.report.Compliance.Passing++
+	}
+}
+
+// add records a violation at the configured severity of its rule and reports
+// whether it is an error. Violations of disabled rules are dropped.
+func (l *linter) add(v Violation) bool {
+	v.Severity = l.cfg.Severity(v.Rule)
+	if v.Severity == SeverityOff {
+		return false
+	}
+
+	l.report.Violations = append(l.report.Violations, v)
+	return v.Severity == SeverityError
+}
+
 func findNameIndex(nodes []*yaml.Node) (int, error) {
 	for index, node := range nodes {
 		if node.Kind == yaml.ScalarNode && node.Value == "name" {
@@ -175,36 +425,25 @@ func findNameIndex(nodes []*yaml.Node) (int, error) {
 	return -1, fmt.Errorf("name field not found")
 }
 
-func getName(configNode *yaml.Node) (string, error) {
+// quoteWidth returns the width of the opening quote of a scalar node.
+func quoteWidth(node *yaml.Node) int {
+	if node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// getName returns the value node of the name field of a workflow or job.
+func getName(configNode *yaml.Node) (*yaml.Node, error) {
 	index, err := findNameIndex(configNode.Content)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(configNode.Content) < index+yamlKeyValuePairSize {
-		return "", fmt.Errorf("name field has no value")
-	}
-
-	nameNode := configNode.Content[index+1]
-	return nameNode.Value, nil
-}
-
-func startsWithEmoji(s string) bool {
-	r := []rune(s)
-	if len(r) == 0 {
-		return false
+		return nil, fmt.Errorf("name field has no value")
 	}
 
-	emoji := r[0]
-	return (emoji >= 0x1F600 && emoji <= 0x1F64F) ||
-		(emoji >= 0x1F300 && emoji <= 0x1F5FF) ||
-		(emoji >= 0x1F680 && emoji <= 0x1F6FF) ||
-		(emoji >= 0x1F1E0 && emoji <= 0x1F1FF) ||
-		(emoji >= 0x2600 && emoji <= 0x26FF) ||
-		(emoji >= 0x2700 && emoji <= 0x27BF) ||
-		(emoji >= 0x1F900 && emoji <= 0x1F9FF) ||
-		(emoji >= 0x1FA00 && emoji <= 0x1FA6F) ||
-		(emoji >= 0x1F004 && emoji <= 0x1F0CF) ||
-		(emoji >= 0x2300 && emoji <= 0x23FF) ||
-		(emoji >= 0x2B00 && emoji <= 0x2BFF)
+	return configNode.Content[index+1], nil
 }
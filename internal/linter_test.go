@@ -185,6 +185,11 @@ func TestLintWorkflows_Snapshots(t *testing.T) {
 			workflowFile: "testdata/missing_workflow_name.yml",
 			goldenFile:   "testdata/missing_workflow_name.golden.json",
 		},
+		{
+			name:         "shortcode snapshot",
+			workflowFile: "testdata/shortcode_workflow.yml",
+			goldenFile:   "testdata/shortcode_workflow.golden.json",
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,8 +236,8 @@ func TestLintWorkflows_Snapshots(t *testing.T) {
 	}
 }
 
-// TestStartsWithEmoji tests only the emoji detection logic (kept minimal for edge cases)
-func TestStartsWithEmoji(t *testing.T) {
+// TestLeadingEmoji_EdgeCases tests only the emoji detection logic (kept minimal for edge cases)
+func TestLeadingEmoji_EdgeCases(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected bool
@@ -242,6 +247,18 @@ func TestStartsWithEmoji(t *testing.T) {
 		{"☀️ Weather", true},
 		{"⭐ Star", true},
 		{"🐹 Gopher", true},
+		{"🫠 Melting", true},
+		{"🪴 Plant", true},
+		{"🫡 Salute", true},
+		{"⬆️ Upgrade", true},
+		{"⌘ Command", false},
+		{"⌀ Diameter", false},
+		{"⮕ Arrow", false},
+		{"1 Setup", false},
+		{"1\ufe0f\u20e3 Setup", true},
+		{"🇺🇸 Release", true},
+		{"🏽 Skin tone", false},
+		{"# Heading", false},
 		{"Deploy", false},
 		{"", false},
 		{" 🚀 Deploy", false},
@@ -249,9 +266,9 @@ func TestStartsWithEmoji(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := startsWithEmoji(tt.input)
+			_, result := LeadingEmoji(tt.input)
 			if result != tt.expected {
-				t.Errorf("startsWithEmoji(%q) = %v, want %v", tt.input, result, tt.expected)
+				t.Errorf("LeadingEmoji(%q) ok = %v, want %v", tt.input, result, tt.expected)
 			}
 		})
 	}
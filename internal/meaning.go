@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// topics are what names are about, with the words that give a name its topic.
+// A word belongs to a topic when it is one of its keywords, or one with an
+// inflection such as "tests" or "caching", but not a longer word that merely
+// starts with one: "fixture" is not a fix.
+var topics = []struct {
+	Name     string
+	Keywords []string
+}{
+	{"setup", []string{"setup", "install", "checkout", "configure", "prepare", "bootstrap", "init"}},
+	{"building", []string{"build", "compile", "bundle", "assemble"}},
+	{"testing", []string{"test", "e2e", "coverage", "benchmark"}},
+	{"linting", []string{"lint", "format", "fmt", "vet", "prettier"}},
+	{"security", []string{"security", "scan", "audit", "codeql", "vulnerability", "vulnerabilities", "secret"}},
+	{"fixes", []string{"hotfix", "fix", "patch"}},
+	{"deployment", []string{"deploy", "release", "publish", "ship", "rollout", "promote"}},
+	{"caching", []string{"cache", "warm"}},
+	{"docs", []string{"docs", "document", "documentation", "readme", "changelog"}},
+	{"notifications", []string{"notify", "notification", "slack", "announce"}},
+	{"cleanup", []string{"clean", "cleanup", "prune", "purge", "delete"}},
+	{"versioning", []string{"version", "bump", "tag"}},
+	{"artifacts", []string{"artifact", "upload", "download"}},
+}
+
+// inflections are the endings a keyword may take in a word of its topic.
+var inflections = []string{"", "s", "es", "d", "ed", "ing", "er", "ers", "ment", "ments"}
+
+// isKeyword reports whether word is keyword or keyword with one of the
+// inflections, dropping a final "e" before "ing" as in "caching".
+func isKeyword(word, keyword string) bool {
+	rest, ok := strings.CutPrefix(word, keyword)
+	if !ok {
+		stem, e := strings.CutSuffix(keyword, "e")
+		return e && word == stem+"ing"
+	}
+
+	return slices.Contains(inflections, rest)
+}
+
+// minTopicalNames is the number of names with a topic an emoji needs before
+// it can be told to stand for unrelated things: a name about caching and one
+// about testing are too few to tell.
+const minTopicalNames = 3
+
+// meaningRef is a name carrying an emoji, its text without the emoji and the
+// topic of that text, if any.
+type meaningRef struct {
+	consistencyRef
+	Text  string
+	Topic string
+}
+
+// EmojiMeaning summarizes what the names carrying one emoji are about.
+type EmojiMeaning struct {
+	Emoji Emoji
+	// Names is the number of names carrying the emoji, with or without a topic.
+	Names int
+	// Topics counts the topics of those names, most common first.
+	Topics []Count
+	// Unrelated is set when no topic accounts for more than half of the
+	// names with a topic, and there are at least minTopicalNames of those:
+	// the emoji stands for nothing in particular.
+	Unrelated bool
+}
+
+// topicOf returns the topic of the first word of text that has one, or "".
+func topicOf(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		for _, topic := range topics {
+			for _, keyword := range topic.Keywords {
+				if isKeyword(word, keyword) {
+					return topic.Name
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (c *Consistency) addMeaning(ref consistencyRef, text string) {
+	key := consistencyKey(ref.Emoji)
+	if _, ok := c.meanings[key]; !ok {
+		c.meaningOrder = append(c.meaningOrder, key)
+	}
+	c.meanings[key] = append(c.meanings[key], meaningRef{consistencyRef: ref, Text: text, Topic: topicOf(text)})
+}
+
+// Meanings returns what each emoji stands for across the collected names,
+// the legend of the workflows, with the most used emoji first.
+func (c *Consistency) Meanings() []EmojiMeaning {
+	meanings := make([]EmojiMeaning, 0, len(c.meaningOrder))
+	for _, key := range c.meaningOrder {
+		meanings = append(meanings, meaningOf(c.meanings[key]))
+	}
+	sort.SliceStable(meanings, func(i, j int) bool { return meanings[i].Names > meanings[j].Names })
+
+	return meanings
+}
+
+// meaningOf counts the topics of the names carrying one emoji, named as the
+// first of them writes it. Topics seen equally often keep the order they were
+// first seen in.
+func meaningOf(refs []meaningRef) EmojiMeaning {
+	meaning := EmojiMeaning{Emoji: refs[0].Emoji, Names: len(refs)}
+
+	counts := map[string]int{}
+	var order []string
+	for _, ref := range refs {
+		if ref.Topic == "" {
+			continue
+		}
+		if counts[ref.Topic] == 0 {
+			order = append(order, ref.Topic)
+		}
+		counts[ref.Topic]++
+	}
+
+	topical := 0
+	for _, topic := range order {
+		meaning.Topics = append(meaning.Topics, Count{Value: topic, Count: counts[topic]})
+		topical += counts[topic]
+	}
+	sort.SliceStable(meaning.Topics, func(i, j int) bool { return meaning.Topics[i].Count > meaning.Topics[j].Count })
+
+	meaning.Unrelated = topical >= minTopicalNames && meaning.Topics[0].Count*2 <= topical
+	return meaning
+}
+
+// meaningViolations adds a violation for each emoji used for unrelated
+// meanings, reported once at the first name carrying it with a topic.
+func (c *Consistency) meaningViolations(violations map[string][]Violation) {
+	for _, key := range c.meaningOrder {
+		refs := c.meanings[key]
+		meaning := meaningOf(refs)
+		if !meaning.Unrelated {
+			continue
+		}
+
+		described := make([]string, len(meaning.Topics))
+		for i, topic := range meaning.Topics {
+			example := firstWithTopic(refs, topic.Value)
+			described[i] = fmt.Sprintf("%s (\"%s\" at %s:%d)", topic.Value, example.Text, example.File, example.Line)
+			if topic.Count > 1 {
+				described[i] = fmt.Sprintf("%s (%d names, e.g. \"%s\" at %s:%d)", topic.Value, topic.Count, example.Text, example.File, example.Line)
+			}
+		}
+		first := firstWithTopic(refs, "")
+
+		severity := first.cfg.Severity(RuleEmojiMeaning)
+		if severity == SeverityOff {
+			continue
+		}
+		violations[first.File] = append(violations[first.File], Violation{
+			Type:       first.Type,
+			Identifier: first.Identifier,
+			Msg:        fmt.Sprintf("Emoji %s is used for unrelated meanings: %s", first.Emoji.Text, strings.Join(described, ", ")),
+			Rule:       RuleEmojiMeaning,
+			Severity:   severity,
+			Line:       first.Line,
+			Column:     first.Column,
+		})
+	}
+}
+
+// firstWithTopic returns the first name about topic or, for "", the first
+// name with any topic.
+func firstWithTopic(refs []meaningRef, topic string) *meaningRef {
+	for i := range refs {
+		if refs[i].Topic != "" && (topic == "" || refs[i].Topic == topic) {
+			return &refs[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+)
+
+// meaningFiles are the workflows of the emoji meaning tests, in the order they are added.
+var meaningFiles = []string{
+	"testdata/meaning/deploy.yml",
+	"testdata/meaning/cache.yml",
+	"testdata/meaning/perf.yml",
+}
+
+// collectMeanings adds meaningFiles to a Consistency, linted with cfg.
+func collectMeanings(t *testing.T, cfg *Config) *Consistency {
+	t.Helper()
+
+	consistency := NewConsistency()
+	for _, file := range meaningFiles {
+		node, err := ParseYAML(file)
+		if err != nil {
+			t.Fatalf("Failed to parse workflow: %v", err)
+		}
+		consistency.Add(file, node, cfg)
+	}
+
+	return consistency
+}
+
+// TestTopicOf tests that the first word with a known keyword gives a name its topic
+func TestTopicOf(t *testing.T) {
+	tests := []struct {
+		text  string
+		topic string
+	}{
+		{"Run tests", "testing"},
+		{"Hotfix deploy", "fixes"},
+		{"Deploy hotfix", "deployment"},
+		{"Warm cache", "caching"},
+		{"Upload coverage", "artifacts"},
+		{"Run E2E suite", "testing"},
+		{"Say hello", ""},
+		{"Testing matrix", "testing"},
+		{"Caching deps", "caching"},
+		{"Deployment", "deployment"},
+		{"Load fixtures", ""},
+		{"Publishing docs", "deployment"},
+		{"Latest tags", "versioning"},
+		{"Check attestations", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := topicOf(tt.text); got != tt.topic {
+				t.Errorf("topicOf(%q) = %q, want %q", tt.text, got, tt.topic)
+			}
+		})
+	}
+}
+
+// TestMeanings tests the legend of what each emoji stands for
+func TestMeanings(t *testing.T) {
+	var got []string
+	for _, m := range collectMeanings(t, DefaultConfig()).Meanings() {
+		got = append(got, fmt.Sprintf("%s %d %v %t", m.Emoji.Text, m.Names, m.Topics, m.Unrelated))
+	}
+
+	expected := []string{
+		"🔥 4 [{fixes 1} {caching 1} {testing 1}] true",
+		"🚀 3 [{deployment 3}] false",
+		"📥 3 [{setup 3}] false",
+		"🗄️ 1 [{caching 1}] false",
+		"📈 1 [] false",
+		"🫱🏻‍🫲🏼 1 [] false",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d emoji, got %d: %q", len(expected), len(got), got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("meaning %d = %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+// TestMeaningViolations tests that an emoji used for unrelated meanings is reported once
+func TestMeaningViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   func(cfg *Config)
+		expected []string
+	}{
+		{
+			name: "unrelated meanings",
+			expected: []string{
+				`testdata/meaning/deploy.yml:5 [Job] hotfix: Emoji 🔥 is used for unrelated meanings: fixes ("Hotfix deploy" at testdata/meaning/deploy.yml:5), caching ("Warm cache" at testdata/meaning/cache.yml:5), testing ("Load test" at testdata/meaning/perf.yml:5) (warning)`,
+			},
+		},
+		{
+			// Two names with a topic are too few to tell the emoji is ambiguous
+			name: "ignored job",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Ignore = []string{"^hotfix$"}
+				cfg.Rules[RuleEmojiMeaning] = SeverityError
+			},
+		},
+		{
+			// Names without a topic do not make the emoji ambiguous
+			name: "single meaning",
+			config: func(cfg *Config) {
+				cfg.Levels.Job.Ignore = []string{"^(hotfix|warm)$"}
+			},
+		},
+		{
+			name: "rule off",
+			config: func(cfg *Config) {
+				cfg.Rules[RuleEmojiMeaning] = SeverityOff
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			compileConfig(t, cfg)
+
+			assertCrossFileViolations(t, meaningFiles, collectMeanings(t, cfg).Violations(), RuleEmojiMeaning, tt.expected)
+		})
+	}
+}
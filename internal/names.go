@@ -0,0 +1,88 @@
+package internal
+
+import "gopkg.in/yaml.v3"
+
+// NameRef is a workflow, job or step display name together with where it was
+// found. Unlike the linter, collecting names never fails on incomplete
+// workflows: entities without a name are returned with Named set to false.
+type NameRef struct {
+	Type  GithubActionType
+	JobID string
+	Name  string
+	Named bool
+	Uses  string
+	Node  *yaml.Node
+}
+
+// CollectNames returns every workflow, job and step name in document order.
+func CollectNames(root *yaml.Node) []NameRef {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+
+	workflowRoot := root.Content[0]
+	if workflowRoot.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	refs := []NameRef{nameRef(Workflow, "", workflowRoot)}
+
+	jobsNode, err := findJobsNode(workflowRoot)
+	if err != nil || jobsNode.Kind != yaml.MappingNode {
+		return refs
+	}
+
+	for i := 0; i+1 < len(jobsNode.Content); i += yamlKeyValuePairSize {
+		jobID := jobsNode.Content[i].Value
+		jobConfig := jobsNode.Content[i+1]
+		refs = append(refs, nameRef(Job, jobID, jobConfig))
+
+		index := findStepsIndex(jobConfig.Content)
+		if index == -1 || index+1 >= len(jobConfig.Content) {
+			continue
+		}
+
+		for _, step := range jobConfig.Content[index+1].Content {
+			ref := nameRef(Step, jobID, step)
+			ref.Uses = mappingValue(step, "uses")
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+func nameRef(t GithubActionType, jobID string, configNode *yaml.Node) NameRef {
+	ref := NameRef{Type: t, JobID: jobID, Node: configNode}
+
+	index, err := findNameIndex(configNode.Content)
+	if err != nil || index+1 >= len(configNode.Content) {
+		return ref
+	}
+
+	ref.Node = configNode.Content[index+1]
+	ref.Name = ref.Node.Value
+	ref.Named = true
+
+	return ref
+}
+
+// mappingValueNode returns the value node of key in a mapping node, or nil.
+func mappingValueNode(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += yamlKeyValuePairSize {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// mappingValue returns the scalar value of key in a mapping node, or "".
+func mappingValue(node *yaml.Node, key string) string {
+	if value := mappingValueNode(node, key); value != nil {
+		return value.Value
+	}
+
+	return ""
+}
@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Placement is where a name must carry its emoji.
+type Placement string
+
+const (
+	// PlacementPrefix requires names to start with an emoji: "🚀 Deploy".
+	PlacementPrefix Placement = "prefix"
+	// PlacementSuffix requires names to end with an emoji: "Deploy 🚀".
+	PlacementSuffix Placement = "suffix"
+	// PlacementWrap requires names to start and end with an emoji: "🚀 Deploy 🚀".
+	PlacementWrap Placement = "wrap"
+	// PlacementAnywhere requires an emoji somewhere in the name: "Deploy 🚀 now".
+	PlacementAnywhere Placement = "anywhere"
+)
+
+// Placements lists every valid placement.
+var Placements = []Placement{PlacementPrefix, PlacementSuffix, PlacementWrap, PlacementAnywhere}
+
+// emojiSide is the end of a name an emoji slot is at.
+type emojiSide int
+
+const (
+	sideStart emojiSide = iota
+	sideEnd
+	sideAnywhere
+)
+
+// emojiSlot is a position of a name where the placement expects an emoji.
+type emojiSlot struct {
+	Side emojiSide
+	// Emoji is the emoji found at the slot, when ok.
+	Emoji Emoji
+	ok    bool
+	// Offset is the byte offset of the emoji or shortcode in the name.
+	Offset int
+	// Shortcode is a GitHub :shortcode: written at the slot instead of an
+	// emoji, and Sequence the emoji it stands for.
+	Shortcode string
+	Sequence  string
+}
+
+// emojiSlots returns the slots a placement expects an emoji at: the start,
+// the end, both, or the first emoji anywhere in the name.
+func emojiSlots(name string, p Placement) []emojiSlot {
+	switch p {
+	case PlacementSuffix:
+		return []emojiSlot{trailingSlot(name)}
+	case PlacementWrap:
+		return []emojiSlot{leadingSlot(name), trailingSlot(name)}
+	case PlacementAnywhere:
+		return []emojiSlot{anywhereSlot(name)}
+	}
+
+	return []emojiSlot{leadingSlot(name)}
+}
+
+func leadingSlot(name string) emojiSlot {
+	if emoji, ok := LeadingEmoji(name); ok {
+		return emojiSlot{Side: sideStart, Emoji: emoji, ok: true}
+	}
+	if shortcode, sequence, ok := LeadingShortcode(name); ok {
+		return emojiSlot{Side: sideStart, Shortcode: shortcode, Sequence: sequence}
+	}
+
+	return emojiSlot{Side: sideStart}
+}
+
+func trailingSlot(name string) emojiSlot {
+	if emoji, ok := TrailingEmoji(name); ok {
+		return emojiSlot{Side: sideEnd, Emoji: emoji, ok: true, Offset: len(name) - len(emoji.Text)}
+	}
+	if shortcode, sequence, ok := TrailingShortcode(name); ok {
+		return emojiSlot{Side: sideEnd, Shortcode: shortcode, Offset: len(name) - len(shortcode), Sequence: sequence}
+	}
+
+	return emojiSlot{Side: sideEnd}
+}
+
+func anywhereSlot(name string) emojiSlot {
+	offset := 0
+	for _, cluster := range graphemes(name) {
+		if emoji, ok := LeadingEmoji(cluster); ok {
+			return emojiSlot{Side: sideAnywhere, Emoji: emoji, ok: true, Offset: offset}
+		}
+		offset += len(cluster)
+	}
+
+	for offset := strings.Index(name, ":"); offset != -1; {
+		if shortcode, sequence, ok := LeadingShortcode(name[offset:]); ok {
+			return emojiSlot{Side: sideAnywhere, Shortcode: shortcode, Offset: offset, Sequence: sequence}
+		}
+		next := strings.Index(name[offset+1:], ":")
+		if next == -1 {
+			break
+		}
+		offset += 1 + next
+	}
+
+	return emojiSlot{Side: sideAnywhere}
+}
+
+// TrailingEmoji returns the emoji a name ends with: its last extended
+// grapheme cluster must be a complete RGI emoji sequence.
+func TrailingEmoji(name string) (Emoji, bool) {
+	clusters := graphemes(name)
+	if len(clusters) == 0 {
+		return Emoji{}, false
+	}
+
+	return LeadingEmoji(clusters[len(clusters)-1])
+}
+
+// placedEmoji returns the emoji of the first slot of a name that carries one,
+// reading a :shortcode: as its emoji and ignoring invisible characters.
+func placedEmoji(name string, p Placement) (Emoji, bool) {
+	name = removeInvisible(name, invisibleCharacters(name))
+	for _, slot := range emojiSlots(name, p) {
+		if slot.ok {
+			return slot.Emoji, true
+		}
+		if slot.Shortcode != "" {
+			return LeadingEmoji(slot.Sequence)
+		}
+	}
+
+	return Emoji{}, false
+}
+
+// countEmoji returns the number of emoji in name, counting whole grapheme
+// clusters so that a ZWJ sequence such as 👨‍👩‍👧 counts once.
+func countEmoji(name string) int {
+	count := 0
+	for _, cluster := range graphemes(name) {
+		if _, ok := LeadingEmoji(cluster); ok {
+			count++
+		}
+	}
+
+	return count
+}
+
+// missingEmojiMessage explains the placement a name does not follow.
+func missingEmojiMessage(p Placement) string {
+	switch p {
+	case PlacementSuffix:
+		return "Name must end with an emoji. Example: 'Deploy 🚀'"
+	case PlacementWrap:
+		return "Name must start and end with an emoji. Example: '🚀 Deploy 🚀'"
+	case PlacementAnywhere:
+		return "Name must contain an emoji. Example: 'Deploy 🚀 to production'"
+	}
+
+	return "Name must start with an emoji. Example: '🚀 Deploy'"
+}
+
+// moveEmoji returns name with its emoji moved to the end the placement
+// expects, for names that carry it at the opposite end.
+func moveEmoji(name string, p Placement, separator string) (string, bool) {
+	switch p {
+	case PlacementPrefix:
+		if emoji, ok := TrailingEmoji(name); ok {
+			rest := trimSeparatorRight(strings.TrimSuffix(name, emoji.Text))
+			return emoji.Text + separator + rest, rest != ""
+		}
+	case PlacementSuffix:
+		if emoji, ok := LeadingEmoji(name); ok {
+			rest := trimSeparatorLeft(strings.TrimPrefix(name, emoji.Text))
+			return rest + separator + emoji.Text, rest != ""
+		}
+	}
+
+	return "", false
+}
+
+// isSeparatorMark reports whether r may separate an emoji from the text of
+// a name: a dash, bar, colon, dot or arrow.
+func isSeparatorMark(r rune) bool {
+	return strings.ContainsRune("-–—|:·•»", r)
+}
+
+// trimSeparatorLeft trims the separator at the start of s. Marks only count
+// when whitespace or the end of s follows them, so that "-1 offset" and
+// "--dry-run" keep the punctuation that belongs to them.
+func trimSeparatorLeft(s string) string {
+	for s != "" {
+		r, size := utf8.DecodeRuneInString(s)
+		if !unicode.IsSpace(r) {
+			marks := strings.TrimLeftFunc(s, isSeparatorMark)
+			next, _ := utf8.DecodeRuneInString(marks)
+			if len(marks) == len(s) || (marks != "" && !unicode.IsSpace(next)) {
+				break
+			}
+			size = len(s) - len(marks)
+		}
+		s = s[size:]
+	}
+
+	return s
+}
+
+// trimSeparatorRight trims the separator at the end of s. Marks only count
+// when whitespace or the start of s precedes them.
+func trimSeparatorRight(s string) string {
+	for s != "" {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if !unicode.IsSpace(r) {
+			marks := strings.TrimRightFunc(s, isSeparatorMark)
+			previous, _ := utf8.DecodeLastRuneInString(marks)
+			if len(marks) == len(s) || (marks != "" && !unicode.IsSpace(previous)) {
+				break
+			}
+			size = len(s) - len(marks)
+		}
+		s = s[:len(s)-size]
+	}
+
+	return s
+}
+
+// separator returns the separator between the emoji of a slot at the start
+// or end of name and the text of the name, along with the byte offset of the
+// emoji and separator together. ok is false when there is no text beside the
+// emoji, or when another emoji follows it as in "🚀🔥 Deploy".
+func (s emojiSlot) separator(name string) (separator string, offset int, ok bool) {
+	switch s.Side {
+	case sideStart:
+		rest := name[s.Offset+len(s.Emoji.Text):]
+		text := trimSeparatorLeft(rest)
+		_, emoji := LeadingEmoji(text)
+		return rest[:len(rest)-len(text)], s.Offset, text != "" && !emoji
+	case sideEnd:
+		before := name[:s.Offset]
+		text := trimSeparatorRight(before)
+		_, emoji := TrailingEmoji(text)
+		return before[len(text):], len(text), text != "" && !emoji
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestEmojiSlots_Placements tests that every slot a placement expects an emoji at holds one
+func TestEmojiSlots_Placements(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   bool
+		suffix   bool
+		wrap     bool
+		anywhere bool
+	}{
+		{"🚀 Deploy", true, false, false, true},
+		{"Deploy 🚀", false, true, false, true},
+		{"🚀 Deploy 🎉", true, true, true, true},
+		{"Deploy 🚀 now", false, false, false, true},
+		{"🚀", true, true, true, true},
+		{"Deploy 🧑\u200d💻", false, true, false, true},
+		{"Deploy ☀️", false, true, false, true},
+		{"Deploy 🏽", false, false, false, false},
+		{"Deploy", false, false, false, false},
+		{"", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := map[Placement]bool{
+				PlacementPrefix:   tt.prefix,
+				PlacementSuffix:   tt.suffix,
+				PlacementWrap:     tt.wrap,
+				PlacementAnywhere: tt.anywhere,
+			}
+			for _, placement := range Placements {
+				missing := slices.ContainsFunc(emojiSlots(tt.name, placement), func(s emojiSlot) bool { return !s.ok })
+				if missing == expected[placement] {
+					t.Errorf("emojiSlots(%q, %s) hold an emoji = %v, want %v", tt.name, placement, !missing, expected[placement])
+				}
+			}
+		})
+	}
+}
+
+// TestEmojiSlots_Shortcodes tests that shortcodes are found where the placement expects an emoji
+func TestEmojiSlots_Shortcodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement Placement
+		shortcode string
+		offset    int
+	}{
+		{":rocket: Deploy", PlacementPrefix, ":rocket:", 0},
+		{"Deploy :rocket:", PlacementSuffix, ":rocket:", 7},
+		{"Deploy :rocket:", PlacementPrefix, "", 0},
+		{"Deploy: now :rocket: x", PlacementAnywhere, ":rocket:", 12},
+		{"Deploy 🚀 :rocket:", PlacementAnywhere, "", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := emojiSlots(tt.name, tt.placement)
+			if len(slots) != 1 {
+				t.Fatalf("emojiSlots(%q, %s) returned %d slots, want 1", tt.name, tt.placement, len(slots))
+			}
+			if slots[0].Shortcode != tt.shortcode || slots[0].Offset != tt.offset {
+				t.Errorf("emojiSlots(%q, %s) = %q at %d, want %q at %d",
+					tt.name, tt.placement, slots[0].Shortcode, slots[0].Offset, tt.shortcode, tt.offset)
+			}
+		})
+	}
+}
+
+// TestLintWorkflowReport_Placement tests placement messages and the fixes that move emoji
+func TestLintWorkflowReport_Placement(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Levels.Workflow.Placement = PlacementSuffix
+	cfg.Levels.Job.Placement = PlacementAnywhere
+	cfg.Levels.Step.Placement = PlacementWrap
+
+	node, err := ParseYAML("testdata/valid_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	// Every job name contains an emoji, no step name ends with one
+	if len(report.Violations) != 7 {
+		t.Fatalf("Expected 7 violations, got %d: %+v", len(report.Violations), report.Violations)
+	}
+
+	workflow := report.Violations[0]
+	if workflow.Type != Workflow || workflow.Msg != "Name must end with an emoji. Example: 'Deploy 🚀'" {
+		t.Errorf("workflow violation = [%s] %s", workflow.Type, workflow.Msg)
+	}
+	expectedFix := Fix{Line: 1, Column: 7, Old: "🚀 Valid Workflow", New: "Valid Workflow 🚀"}
+	if workflow.Fix == nil || *workflow.Fix != expectedFix {
+		t.Errorf("workflow fix = %+v, want %+v", workflow.Fix, expectedFix)
+	}
+
+	for _, v := range report.Violations[1:] {
+		if v.Type != Step || v.Msg != "Name must start and end with an emoji. Example: '🚀 Deploy 🚀'" || v.Fix != nil {
+			t.Errorf("step violation = [%s] %s: %s (fix %+v)", v.Type, v.Identifier, v.Msg, v.Fix)
+		}
+	}
+}
+
+// TestCountEmoji tests that sequences count as a single emoji
+func TestCountEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int
+	}{
+		{"Deploy", 0},
+		{"🚀 Deploy", 1},
+		{"🚀🔥✨💯 Deploy", 4},
+		{"👨\u200d👩\u200d👧 Family", 1},
+		{"👍🏽 Approve", 1},
+		{"🇺🇸🇩🇪 Release", 2},
+		{"1\ufe0f\u20e3 Step 1", 1},
+		{"Step #1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countEmoji(tt.name); got != tt.expected {
+				t.Errorf("countEmoji(%q) = %d, want %d", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestEmojiSlot_Separator tests the separator found between an emoji and the text
+func TestEmojiSlot_Separator(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement Placement
+		separator string
+		ok        bool
+	}{
+		{"🚀 Deploy", PlacementPrefix, " ", true},
+		{"🚀Deploy", PlacementPrefix, "", true},
+		{"🚀 - Deploy", PlacementPrefix, " - ", true},
+		{"🚀| Deploy", PlacementPrefix, "| ", true},
+		{"🚀 -- Deploy", PlacementPrefix, " -- ", true},
+		{"🔢 -1 offset", PlacementPrefix, " ", true},
+		{"🧪 --dry-run", PlacementPrefix, " ", true},
+		{"🔀 > merge queue", PlacementPrefix, " ", true},
+		{"🚀 -", PlacementPrefix, " -", false},
+		{"🚀 [ci] Deploy", PlacementPrefix, " ", true},
+		{"Deploy — 🚀", PlacementSuffix, " — ", true},
+		{"Deploy🚀", PlacementSuffix, "", true},
+		{"Deploy: 🚀", PlacementSuffix, " ", true},
+		{"🚀🔥 Deploy", PlacementPrefix, "", false},
+		{"🚀 ", PlacementPrefix, " ", false},
+		{"Deploy 🚀 now", PlacementAnywhere, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			separator, _, ok := emojiSlots(tt.name, tt.placement)[0].separator(tt.name)
+			if separator != tt.separator || ok != tt.ok {
+				t.Errorf("separator(%q, %s) = %q, %v, want %q, %v", tt.name, tt.placement, separator, ok, tt.separator, tt.ok)
+			}
+		})
+	}
+}
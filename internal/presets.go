@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed presets/*.yml
+var presetFiles embed.FS
+
+// Presets lists the names of the built-in configs that can be used in `extends:`.
+func Presets() []string {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yml"))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// isPresetRef reports whether an `extends:` entry names a built-in preset
+// rather than a config file. File references contain a slash or a YAML extension.
+func isPresetRef(ref string) bool {
+	ext := path.Ext(ref)
+	return !strings.ContainsAny(ref, `/\`) && ext != ".yml" && ext != ".yaml"
+}
+
+func readPreset(name string) ([]byte, bool) {
+	data, err := presetFiles.ReadFile("presets/" + name + ".yml")
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
@@ -0,0 +1,108 @@
+package internal
+
+import "testing"
+
+// TestPresets_Valid tests that every built-in preset passes config validation
+func TestPresets_Valid(t *testing.T) {
+	expected := []string{"gitmoji", "relaxed", "steps-optional", "strict"}
+
+	presets := Presets()
+	if len(presets) != len(expected) {
+		t.Fatalf("Presets() = %v, want %v", presets, expected)
+	}
+
+	for i, name := range presets {
+		if name != expected[i] {
+			t.Errorf("preset %d = %q, want %q", i, name, expected[i])
+		}
+
+		data, _ := readPreset(name)
+		problems, err := ValidateConfig(data)
+		if err != nil {
+			t.Fatalf("ValidateConfig(%s) failed: %v", name, err)
+		}
+		for _, p := range problems {
+			t.Errorf("preset %s: %s", name, p)
+		}
+	}
+}
+
+// TestPresets_Strict tests that the strict preset turns every rule into an error
+func TestPresets_Strict(t *testing.T) {
+	layers := NewConfigLayers()
+	if err := layers.addPreset("strict", nil); err != nil {
+		t.Fatalf("addPreset() failed: %v", err)
+	}
+
+	resolved, err := layers.Resolve("")
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	for _, rule := range Rules {
+		if got := resolved.Sources["rules."+rule.ID]; got != "preset strict" {
+			t.Errorf("strict preset does not set rule %s (source %q)", rule.ID, got)
+		}
+		if got := resolved.Config.Severity(rule.ID); got != SeverityError {
+			t.Errorf("Severity(%s) = %s, want %s", rule.ID, got, SeverityError)
+		}
+	}
+}
+
+// TestLintWorkflowReport_Presets tests the effect of presets on linting
+func TestLintWorkflowReport_Presets(t *testing.T) {
+	tests := []struct {
+		name         string
+		preset       string
+		workflowFile string
+		expected     map[string]int
+	}{
+		{
+			name:         "steps-optional skips step names",
+			preset:       "steps-optional",
+			workflowFile: "testdata/invalid_workflow.yml",
+			expected:     map[string]int{RuleRequireEmoji: 3},
+		},
+		{
+			name:         "gitmoji rejects emoji outside the guide",
+			preset:       "gitmoji",
+			workflowFile: "testdata/valid_workflow.yml",
+			expected:     map[string]int{RuleAllowedEmoji: 3}, // 📥 twice and 🐹
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := NewConfigLayers()
+			if err := layers.addPreset(tt.preset, nil); err != nil {
+				t.Fatalf("addPreset() failed: %v", err)
+			}
+			resolved, err := layers.Resolve("")
+			if err != nil {
+				t.Fatalf("Resolve() failed: %v", err)
+			}
+
+			node, err := ParseYAML(tt.workflowFile)
+			if err != nil {
+				t.Fatalf("Failed to parse workflow: %v", err)
+			}
+			report, err := LintWorkflowReport(node, resolved.Config)
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			counts := map[string]int{}
+			for _, v := range report.Violations {
+				counts[v.Rule]++
+			}
+			if len(counts) != len(tt.expected) {
+				t.Errorf("violations by rule = %v, want %v", counts, tt.expected)
+			}
+			for rule, count := range tt.expected {
+				if counts[rule] != count {
+					t.Errorf("%s violations = %d, want %d", rule, counts[rule], count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultRatchetFile is where the compliance floor is stored when no other path is given.
+const DefaultRatchetFile = ".emojigate-ratchet.json"
+
+// Compliance counts how many workflow, job and step names pass every check.
+type Compliance struct {
+	Names   int `json:"names"`
+	Passing int `json:"passing"`
+}
+
+// Percent returns the share of passing names. A scope without names is fully compliant.
+func (c Compliance) Percent() float64 {
+	if c.Names == 0 {
+		return 100
+	}
+
+	return float64(c.Passing) * 100 / float64(c.Names)
+}
+
+// Add accumulates another compliance count into c.
+func (c *Compliance) Add(other Compliance) {
+	c.Names += other.Names
+	c.Passing += other.Passing
+}
+
+// lessThan reports whether c is less compliant than floor. The comparison is
+// done on the raw counts so rounding never produces a false regression.
+func (c Compliance) lessThan(floor Compliance) bool {
+	c, floor = c.normalized(), floor.normalized()
+	return c.Passing*floor.Names < floor.Passing*c.Names
+}
+
+// normalized maps an empty scope to a single passing name, matching Percent.
+func (c Compliance) normalized() Compliance {
+	if c.Names == 0 {
+		return Compliance{Names: 1, Passing: 1}
+	}
+
+	return c
+}
+
+// Ratchet is the compliance floor recorded per workflow file. Compliance may
+// go up freely but must never drop below the recorded values.
+type Ratchet struct {
+	Files map[string]Compliance `json:"files"`
+}
+
+// Regression describes a scope whose compliance dropped below its floor.
+// Scope is either a workflow file path or "repository".
+type Regression struct {
+	Scope   string
+	Floor   Compliance
+	Current Compliance
+}
+
+// LoadRatchet reads the ratchet state file. A missing file yields an empty
+// ratchet, so the first run establishes the floor.
+func LoadRatchet(path string) (*Ratchet, error) {
+	ratchet := &Ratchet{Files: map[string]Compliance{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ratchet, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ratchet file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, ratchet); err != nil {
+		return nil, fmt.Errorf("failed to parse ratchet file: %w", err)
+	}
+	if ratchet.Files == nil {
+		ratchet.Files = map[string]Compliance{}
+	}
+
+	return ratchet, nil
+}
+
+// Save writes the ratchet state file.
+func (r *Ratchet) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ratchet: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write ratchet file: %w", err)
+	}
+
+	return nil
+}
+
+// Repository returns the repository-wide compliance when the given files are
+// combined with the recorded state of every file that was not linted.
+func (r *Ratchet) Repository(current map[string]Compliance) Compliance {
+	var total Compliance
+	for _, c := range current {
+		total.Add(c)
+	}
+	for file, c := range r.Files {
+		if _, ok := current[file]; !ok {
+			total.Add(c)
+		}
+	}
+
+	return total
+}
+
+// Check compares the current per-file compliance against the recorded floor and
+// returns every file, and the repository as a whole, whose compliance dropped.
+// Files without a recorded floor never regress.
+func (r *Ratchet) Check(current map[string]Compliance) []Regression {
+	var regressions []Regression
+
+	for _, file := range sortedFiles(current) {
+		floor, ok := r.Files[file]
+		if !ok {
+			continue
+		}
+		if current[file].lessThan(floor) {
+			regressions = append(regressions, Regression{Scope: file, Floor: floor, Current: current[file]})
+		}
+	}
+
+	floor := r.Repository(nil)
+	repository := r.Repository(current)
+	if len(r.Files) > 0 && repository.lessThan(floor) {
+		regressions = append(regressions, Regression{Scope: "repository", Floor: floor, Current: repository})
+	}
+
+	return regressions
+}
+
+// Raise records the current compliance of every file that did not regress.
+// Files that regressed keep their previous floor.
+func (r *Ratchet) Raise(current map[string]Compliance) {
+	for file, c := range current {
+		floor, ok := r.Files[file]
+		if ok && c.lessThan(floor) {
+			continue
+		}
+		r.Files[file] = c
+	}
+}
+
+// Prune removes the floor of every recorded file that no longer exists, so
+// that deleted workflows stop counting towards the repository. It is only
+// meant for runs over every workflow, and returns the removed files.
+func (r *Ratchet) Prune() []string {
+	var removed []string
+	for _, file := range sortedFiles(r.Files) {
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			delete(r.Files, file)
+			removed = append(removed, file)
+		}
+	}
+
+	return removed
+}
+
+func sortedFiles(files map[string]Compliance) []string {
+	keys := make([]string, 0, len(files))
+	for file := range files {
+		keys = append(keys, file)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
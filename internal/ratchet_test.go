@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLintWorkflowReport_Compliance checks that every name is counted exactly once
+func TestLintWorkflowReport_Compliance(t *testing.T) {
+	tests := []struct {
+		workflowFile string
+		expected     Compliance
+	}{
+		{"testdata/valid_workflow.yml", Compliance{Names: 10, Passing: 10}},
+		{"testdata/invalid_workflow.yml", Compliance{Names: 8, Passing: 1}},
+		{"testdata/missing_job_name.yml", Compliance{Names: 5, Passing: 4}},
+		{"testdata/missing_workflow_name.yml", Compliance{Names: 3, Passing: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.workflowFile, func(t *testing.T) {
+			node, err := ParseYAML(tt.workflowFile)
+			if err != nil {
+				t.Fatalf("Failed to parse workflow: %v", err)
+			}
+
+			report, err := LintWorkflowReport(node, DefaultConfig())
+			if err != nil {
+				t.Fatalf("Linting failed: %v", err)
+			}
+
+			if report.Compliance != tt.expected {
+				t.Errorf("Compliance = %+v, want %+v", report.Compliance, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRatchet_Check tests regression detection per file and for the repository
+func TestRatchet_Check(t *testing.T) {
+	ratchet := &Ratchet{Files: map[string]Compliance{
+		"a.yml": {Names: 10, Passing: 5},
+		"b.yml": {Names: 4, Passing: 4},
+	}}
+
+	tests := []struct {
+		name     string
+		current  map[string]Compliance
+		expected []string
+	}{
+		{
+			name:    "unchanged",
+			current: map[string]Compliance{"a.yml": {Names: 10, Passing: 5}},
+		},
+		{
+			name:    "improved file",
+			current: map[string]Compliance{"a.yml": {Names: 10, Passing: 8}},
+		},
+		{
+			name:     "regressed file",
+			current:  map[string]Compliance{"b.yml": {Names: 4, Passing: 3}},
+			expected: []string{"b.yml", "repository"},
+		},
+		{
+			name:    "same ratio with more names",
+			current: map[string]Compliance{"a.yml": {Names: 20, Passing: 10}, "b.yml": {Names: 8, Passing: 8}},
+		},
+		{
+			name:     "new non-compliant file lowers repository",
+			current:  map[string]Compliance{"c.yml": {Names: 3, Passing: 0}},
+			expected: []string{"repository"},
+		},
+		{
+			name:     "file emptied of passing names",
+			current:  map[string]Compliance{"b.yml": {Names: 0, Passing: 0}, "a.yml": {Names: 10, Passing: 4}},
+			expected: []string{"a.yml", "repository"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regressions := ratchet.Check(tt.current)
+
+			if len(regressions) != len(tt.expected) {
+				t.Fatalf("Check() returned %d regressions, want %d: %+v", len(regressions), len(tt.expected), regressions)
+			}
+			for i, r := range regressions {
+				if r.Scope != tt.expected[i] {
+					t.Errorf("regression %d scope = %s, want %s", i, r.Scope, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestRatchet_Raise tests that the floor only ever moves up
+func TestRatchet_Raise(t *testing.T) {
+	ratchet := &Ratchet{Files: map[string]Compliance{
+		"a.yml": {Names: 10, Passing: 5},
+		"b.yml": {Names: 4, Passing: 4},
+	}}
+
+	ratchet.Raise(map[string]Compliance{
+		"a.yml": {Names: 10, Passing: 7},
+		"b.yml": {Names: 4, Passing: 2},
+		"c.yml": {Names: 2, Passing: 1},
+	})
+
+	expected := map[string]Compliance{
+		"a.yml": {Names: 10, Passing: 7},
+		"b.yml": {Names: 4, Passing: 4},
+		"c.yml": {Names: 2, Passing: 1},
+	}
+	for file, want := range expected {
+		if got := ratchet.Files[file]; got != want {
+			t.Errorf("floor for %s = %+v, want %+v", file, got, want)
+		}
+	}
+}
+
+// TestRatchet_Prune tests that only the floors of deleted files are dropped
+func TestRatchet_Prune(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.yml")
+	if err := os.WriteFile(kept, []byte("name: 🚀 Kept\n"), 0644); err != nil {
+		t.Fatalf("Failed to write workflow: %v", err)
+	}
+	deleted := filepath.Join(dir, "deleted.yml")
+
+	ratchet := &Ratchet{Files: map[string]Compliance{
+		kept:    {Names: 1, Passing: 1},
+		deleted: {Names: 4, Passing: 1},
+	}}
+
+	removed := ratchet.Prune()
+	if len(removed) != 1 || removed[0] != deleted {
+		t.Errorf("Prune() = %q, want [%q]", removed, deleted)
+	}
+	if _, ok := ratchet.Files[kept]; !ok || len(ratchet.Files) != 1 {
+		t.Errorf("Files = %+v, want only %s", ratchet.Files, kept)
+	}
+}
+
+// TestReport_AddCrossFile tests that cross-file errors count against the names they fail, once,
+// and not against names that were never counted
+func TestReport_AddCrossFile(t *testing.T) {
+	report := &Report{
+		Violations: []Violation{{Rule: RuleRequireEmoji, Severity: SeverityError, Line: 3, Column: 11}},
+		Compliance: Compliance{Names: 4, Passing: 3},
+		counted:    map[position]bool{{1, 7}: true, {3, 11}: true, {5, 13}: true, {7, 13}: true},
+	}
+
+	report.AddCrossFile([]Violation{
+		{Rule: RuleActionEmoji, Severity: SeverityWarning, Line: 5, Column: 13},
+		{Rule: RuleJobEmoji, Severity: SeverityError, Line: 3, Column: 11},
+		{Rule: RuleActionEmoji, Severity: SeverityError, Line: 7, Column: 13},
+		{Rule: RuleDuplicateName, Severity: SeverityError, Line: 7, Column: 13},
+		{Rule: RuleActionEmoji, Severity: SeverityError, Line: 12, Column: 15},
+	})
+
+	if report.Compliance != (Compliance{Names: 4, Passing: 2}) {
+		t.Errorf("Compliance = %+v, want {Names:4 Passing:2}", report.Compliance)
+	}
+	if len(report.Violations) != 6 {
+		t.Errorf("Expected 6 violations, got %d", len(report.Violations))
+	}
+}
+
+// TestReport_AddCrossFile_UnnamedJob tests that a cross-file error on a step of a job without a
+// name, which the linter does not count, leaves the compliance of its file alone
+func TestReport_AddCrossFile_UnnamedJob(t *testing.T) {
+	files := []string{"testdata/consistency/ci.yml", "testdata/consistency/release.yml", "testdata/unnamed_job_workflow.yml"}
+	cfg := DefaultConfig()
+	cfg.Rules[RuleActionEmoji] = SeverityError
+	compileConfig(t, cfg)
+
+	consistency := NewConsistency()
+	for _, file := range files {
+		node, err := ParseYAML(file)
+		if err != nil {
+			t.Fatalf("Failed to parse workflow: %v", err)
+		}
+		consistency.Add(file, node, cfg)
+	}
+
+	node, err := ParseYAML(files[2])
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	crossFile := consistency.Violations()[files[2]]
+	assertRuleViolations(t, crossFile, RuleActionEmoji, []string{
+		"[Step] 🛒 Checkout: Emoji 🛒 differs from 📥 used by 2 other step(s) with actions/checkout: testdata/consistency/ci.yml:8, testdata/consistency/release.yml:8 (error)",
+	})
+
+	report.AddCrossFile(crossFile)
+	if report.Compliance != (Compliance{Names: 2, Passing: 1}) {
+		t.Errorf("Compliance = %+v, want {Names:2 Passing:1}", report.Compliance)
+	}
+}
+
+// TestRatchet_SaveLoad tests the state file round trip, including a missing file
+func TestRatchet_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultRatchetFile)
+
+	empty, err := LoadRatchet(path)
+	if err != nil {
+		t.Fatalf("LoadRatchet() failed for missing file: %v", err)
+	}
+	if len(empty.Files) != 0 {
+		t.Errorf("LoadRatchet() for missing file returned %d files, want 0", len(empty.Files))
+	}
+
+	empty.Raise(map[string]Compliance{"a.yml": {Names: 3, Passing: 2}})
+	if err := empty.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, err := LoadRatchet(path)
+	if err != nil {
+		t.Fatalf("LoadRatchet() failed: %v", err)
+	}
+	if got := loaded.Files["a.yml"]; got != (Compliance{Names: 3, Passing: 2}) {
+		t.Errorf("loaded floor = %+v, want {Names:3 Passing:2}", got)
+	}
+}
@@ -0,0 +1,73 @@
+package internal
+
+// Severity controls how a rule violation is reported.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+// Severities lists every valid severity.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityOff}
+
+// Rule IDs used in violations and in the `rules:` section of the config.
+const (
+	RuleMissingName    = "missing-name"
+	RuleRequireEmoji   = "require-emoji"
+	RuleAllowedEmoji   = "allowed-emoji"
+	RuleEmojiVersion   = "emoji-version"
+	RuleDeniedEmoji    = "denied-emoji"
+	RuleShortcode      = "shortcode"
+	RuleEmojiCount     = "emoji-count"
+	RuleSeparator      = "separator"
+	RuleInvisible      = "invisible-characters"
+	RuleTextStyle      = "text-presentation"
+	RuleInclusive      = "inclusive-emoji"
+	RuleActionEmoji    = "action-emoji"
+	RuleJobEmoji       = "job-emoji"
+	RuleSemantic       = "semantic-emoji"
+	RuleEmojiMeaning   = "emoji-meaning"
+	RuleDuplicateEmoji = "duplicate-emoji"
+	RuleDuplicateName  = "duplicate-name"
+)
+
+// Rule describes a lint rule and its default severity.
+type Rule struct {
+	ID          string
+	Description string
+	Default     Severity
+}
+
+// Rules lists every rule known to the linter.
+var Rules = []Rule{
+	{ID: RuleMissingName, Description: "Workflows and jobs must have a display name", Default: SeverityError},
+	{ID: RuleRequireEmoji, Description: "Names must carry an emoji where levels.*.placement puts it (default: first)", Default: SeverityError},
+	{ID: RuleInvisible, Description: "Names must not contain zero-width, bidi control or other invisible characters", Default: SeverityError},
+	{ID: RuleShortcode, Description: "Names must use a Unicode emoji, not a :shortcode: (not rendered in Actions)", Default: SeverityError},
+	{ID: RuleTextStyle, Description: "Emoji must include U+FE0F when they would otherwise render as monochrome text", Default: SeverityWarning},
+	{ID: RuleAllowedEmoji, Description: "Names must use an emoji from emoji.allow, when set", Default: SeverityError},
+	{ID: RuleDeniedEmoji, Description: "Names must not use an emoji from emoji.deny", Default: SeverityError},
+	{ID: RuleSeparator, Description: "The emoji must be separated from the text by levels.*.separator", Default: SeverityError},
+	{ID: RuleEmojiCount, Description: "Names must not contain more emoji than levels.*.maxEmoji, when set", Default: SeverityError},
+	{ID: RuleEmojiVersion, Description: "Emoji must not be newer than emoji.maxVersion, when set", Default: SeverityError},
+	{ID: RuleSemantic, Description: "Names matching a levels.*.semantics pattern must use one of its emoji", Default: SeverityError},
+	{ID: RuleInclusive, Description: "Emoji must follow emoji.skinTone and emoji.genderNeutral, when set", Default: SeverityError},
+	{ID: RuleActionEmoji, Description: "Steps using the same action must carry the same emoji in every workflow", Default: SeverityWarning},
+	{ID: RuleJobEmoji, Description: "Jobs with the same ID must carry the same emoji in every workflow", Default: SeverityWarning},
+	{ID: RuleDuplicateEmoji, Description: "Jobs of a workflow and steps of a job must not repeat an emoji more than levels.*.maxRepeat", Default: SeverityOff},
+	{ID: RuleDuplicateName, Description: "Steps of a job, jobs of a workflow and workflows must not share a display name", Default: SeverityWarning},
+	{ID: RuleEmojiMeaning, Description: "An emoji must not be used for unrelated meanings across workflows", Default: SeverityWarning},
+}
+
+// FindRule returns the rule with the given ID.
+func FindRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
@@ -0,0 +1,485 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaURL is where the published JSON Schema of .emojigate.yml can be fetched.
+const SchemaURL = "https://raw.githubusercontent.com/FohkinScroob/emojigate/main/schema/emojigate.schema.json"
+
+// Value formats that need checks beyond their YAML type.
+const (
+	formatGlob    = "glob"
+	formatExtends = "extends"
+	formatRegexp  = "regexp"
+	formatVersion = "emoji-version"
+	formatEmoji   = "emoji"
+	// formatSingleEmoji is an emoji entry that is not a group.
+	formatSingleEmoji = "single-emoji"
+)
+
+// schemaNode describes one config key. It drives both `config validate` and
+// the published JSON Schema, so the two can never disagree.
+type schemaNode struct {
+	Type        string
+	Description string
+	Enum        []string
+	Format      string
+	// Minimum is the smallest value of an integer.
+	Minimum    *int
+	Properties map[string]*schemaNode
+	Required   []string
+	// Keys restricts the keys of a map and KeyKind names them in messages.
+	// Values describes the values of a map.
+	Keys    []string
+	KeyKind string
+	Values  *schemaNode
+	Items   *schemaNode
+	// Single lets an array be written as its only item, as in `extends: strict`.
+	Single bool
+}
+
+func configSchema() *schemaNode {
+	properties := policySchema()
+	properties["ignore"] = &schemaNode{
+		Type:        "array",
+		Description: "Glob patterns of workflow files that are not linted.",
+		Items:       &schemaNode{Type: "string", Format: formatGlob},
+	}
+	properties["extends"] = &schemaNode{
+		Type:        "array",
+		Description: "Built-in presets (" + strings.Join(Presets(), ", ") + ") or config files to build on, applied in order. A single one may be given as a string. Paths are relative to this file.",
+		Items:       &schemaNode{Type: "string", Format: formatExtends},
+		Single:      true,
+	}
+
+	override := policySchema()
+	override["files"] = &schemaNode{
+		Type:        "array",
+		Description: "Glob patterns of the workflow files this override applies to.",
+		Items:       &schemaNode{Type: "string", Format: formatGlob},
+	}
+	properties["overrides"] = &schemaNode{
+		Type:        "array",
+		Description: "Settings that only apply to the workflow files matching `files`.",
+		Items:       &schemaNode{Type: "object", Properties: override, Required: []string{"files"}},
+	}
+
+	return &schemaNode{Type: "object", Properties: properties}
+}
+
+// policySchema describes the settings that can differ between workflow files.
+func policySchema() map[string]*schemaNode {
+	return map[string]*schemaNode{
+		"rules": {
+			Type:        "object",
+			Description: "Severity of individual rules by rule ID.",
+			Keys:        ruleIDs(),
+			KeyKind:     "rule ID",
+			Values:      &schemaNode{Type: "string", Enum: severityNames()},
+		},
+		"levels": {
+			Type:        "object",
+			Description: "Settings of workflow, job and step names.",
+			Properties: map[string]*schemaNode{
+				"workflow": levelSchema("workflow", "", ""),
+				"job":      levelSchema("job", "job IDs", "jobs of a workflow"),
+				"step":     levelSchema("step", "step names", "steps of a job"),
+			},
+		},
+		"emoji": {
+			Type:        "object",
+			Description: "Which emoji names may use.",
+			Properties: map[string]*schemaNode{
+				"allow": {
+					Type:        "array",
+					Description: "The only emoji names may use. Empty allows any emoji. " + emojiEntryHelp,
+					Items:       &schemaNode{Type: "string", Format: formatEmoji},
+				},
+				"deny": {
+					Type:        "array",
+					Description: "Emoji names must not use. " + emojiEntryHelp,
+					Items:       &schemaNode{Type: "string", Format: formatEmoji},
+				},
+				"maxVersion": {
+					Type:        "string",
+					Description: "The newest Emoji version names may use, such as 13.0, for renderers without newer emoji.",
+					Format:      formatVersion,
+				},
+				"skinTone": {
+					Type:        "string",
+					Description: "Skin tones of emoji such as 👍🏽: any, default (only the yellow 👍) or consistent (one skin tone per workflow file).",
+					Enum:        skinToneNames(),
+				},
+				"genderNeutral": {
+					Type:        "boolean",
+					Description: "Prefer gender-neutral emoji, such as 🧑‍💻, over gendered ZWJ variants, such as 👩‍💻.",
+				},
+				"actions": {
+					Type:        "object",
+					Description: "The emoji of steps using each action, such as actions/checkout: 📥. Other actions use the emoji most of their steps use.",
+					Values:      &schemaNode{Type: "string", Format: formatSingleEmoji},
+				},
+				"jobs": {
+					Type:        "object",
+					Description: "The emoji of jobs with each ID, such as lint: 🧹. Other job IDs use the emoji most of their jobs use.",
+					Values:      &schemaNode{Type: "string", Format: formatSingleEmoji},
+				},
+			},
+		},
+	}
+}
+
+const emojiEntryHelp = "Entries are emoji (🚀), code points (U+1F680), shortcodes (:rocket:) or emoji groups and subgroups (Travel & Places)."
+
+// levelSchema describes the settings of one kind of name. Levels with an
+// ignore subject accept regular expressions of the entities to skip, and
+// levels with siblings limit how often those repeat an emoji.
+func levelSchema(level, ignoreSubject, siblings string) *schemaNode {
+	zero := 0
+	schema := &schemaNode{
+		Type:        "object",
+		Description: "Settings of " + level + " names.",
+		Properties: map[string]*schemaNode{
+			"enabled": {Type: "boolean", Description: "Whether " + level + " names are linted."},
+			"placement": {
+				Type:        "string",
+				Description: "Where " + level + " names must carry an emoji: prefix (🚀 Deploy), suffix (Deploy 🚀), wrap (🚀 Deploy 🚀) or anywhere.",
+				Enum:        placementNames(),
+			},
+			"separator": {
+				Type:        "string",
+				Description: "The text between the emoji and the rest of " + level + " names, such as \" \" (default) or \" - \". Empty for none.",
+			},
+			"maxEmoji": {
+				Type:        "integer",
+				Description: "The most emoji a " + level + " name may contain, counting ZWJ sequences once. 0 allows any number.",
+				Minimum:     &zero,
+			},
+			"semantics": {
+				Type:        "array",
+				Description: "Emoji that " + level + " names must use based on their text, such as 🚀 or 📦 for names matching (?i)deploy|release. The first matching entry applies.",
+				Items: &schemaNode{
+					Type: "object",
+					Properties: map[string]*schemaNode{
+						"match": {
+							Type:        "string",
+							Description: "Regular expression tested against the name without its emoji.",
+							Format:      formatRegexp,
+						},
+						"emoji": {
+							Type:        "array",
+							Description: "The emoji matching names may use. " + emojiEntryHelp,
+							Items:       &schemaNode{Type: "string", Format: formatEmoji},
+						},
+					},
+					Required: []string{"match", "emoji"},
+				},
+			},
+		},
+	}
+	if ignoreSubject != "" {
+		schema.Properties["ignore"] = &schemaNode{
+			Type:        "array",
+			Description: "Regular expressions of " + ignoreSubject + " that are not linted.",
+			Items:       &schemaNode{Type: "string", Format: formatRegexp},
+		}
+	}
+	if siblings != "" {
+		schema.Properties["maxRepeat"] = &schemaNode{
+			Type:        "integer",
+			Description: "How many " + siblings + " may carry the same emoji, checked by the duplicate-emoji rule. 0 allows any number.",
+			Minimum:     &zero,
+		}
+		schema.Properties["allowRepeat"] = &schemaNode{
+			Type:        "array",
+			Description: "Emoji that any number of " + siblings + " may carry, such as 📥. " + emojiEntryHelp,
+			Items:       &schemaNode{Type: "string", Format: formatEmoji},
+		}
+	}
+
+	return schema
+}
+
+func ruleIDs() []string {
+	ids := make([]string, 0, len(Rules))
+	for _, rule := range Rules {
+		ids = append(ids, rule.ID)
+	}
+
+	return ids
+}
+
+func severityNames() []string {
+	names := make([]string, 0, len(Severities))
+	for _, severity := range Severities {
+		names = append(names, string(severity))
+	}
+
+	return names
+}
+
+func placementNames() []string {
+	names := make([]string, 0, len(Placements))
+	for _, placement := range Placements {
+		names = append(names, string(placement))
+	}
+
+	return names
+}
+
+func skinToneNames() []string {
+	names := make([]string, 0, len(SkinTones))
+	for _, tone := range SkinTones {
+		names = append(names, string(tone))
+	}
+
+	return names
+}
+
+// ConfigProblem is a config error found by ValidateConfig.
+type ConfigProblem struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (p ConfigProblem) String() string {
+	if p.Line == 0 {
+		return p.Msg
+	}
+
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Msg)
+}
+
+// ValidateConfig checks config file contents against the schema and reports
+// unknown keys, values of the wrong type, invalid severities, unknown rule
+// IDs, unknown presets, malformed glob patterns and invalid regular
+// expressions. An error is returned only when the data is not valid YAML.
+func ValidateConfig(data []byte) ([]ConfigProblem, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	var problems []ConfigProblem
+	validateNode(configSchema(), root.Content[0], "", &problems)
+
+	return problems, nil
+}
+
+func validateNode(schema *schemaNode, node *yaml.Node, key string, out *[]ConfigProblem) {
+	problem := func(n *yaml.Node, format string, args ...any) {
+		*out = append(*out, ConfigProblem{Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if schema.Single && node.Kind == yaml.ScalarNode {
+		validateNode(schema.Items, node, key, out)
+		return
+	}
+	if !hasType(node, schema.Type) {
+		problem(node, "%s must be %s", describeKey(key), article(schema.Type))
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		for _, required := range schema.Required {
+			if mappingValueNode(node, required) == nil {
+				problem(node, "missing required key %s", describeKey(joinKey(key, required)))
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += yamlKeyValuePairSize {
+			k, v := node.Content[i], node.Content[i+1]
+			child := joinKey(key, k.Value)
+
+			if schema.Properties != nil {
+				property, ok := schema.Properties[k.Value]
+				if !ok {
+					problem(k, "unknown key %s", describeKey(child))
+					continue
+				}
+				validateNode(property, v, child, out)
+				continue
+			}
+
+			if schema.Keys != nil && !contains(schema.Keys, k.Value) {
+				problem(k, "unknown %s %q in %s", schema.KeyKind, k.Value, describeKey(key))
+				continue
+			}
+			if schema.Values != nil {
+				validateNode(schema.Values, v, child, out)
+			}
+		}
+	case "array":
+		for i, item := range node.Content {
+			validateNode(schema.Items, item, fmt.Sprintf("%s[%d]", key, i), out)
+		}
+	default:
+		if schema.Enum != nil && !contains(schema.Enum, node.Value) {
+			problem(node, "invalid value %q for %s (expected one of: %s)", node.Value, describeKey(key), strings.Join(schema.Enum, ", "))
+		}
+		if schema.Minimum != nil {
+			if value, err := strconv.Atoi(node.Value); err == nil && value < *schema.Minimum {
+				problem(node, "%s must be at least %d", describeKey(key), *schema.Minimum)
+			}
+		}
+		if schema.Format == formatGlob {
+			if _, err := path.Match(node.Value, ""); err != nil {
+				problem(node, "invalid glob pattern %q in %s", node.Value, describeKey(key))
+			}
+		}
+		if schema.Format == formatRegexp {
+			if _, err := regexp.Compile(node.Value); err != nil {
+				problem(node, "invalid regular expression %q in %s", node.Value, describeKey(key))
+			}
+		}
+		if schema.Format == formatEmoji {
+			if _, err := parseEmojiPattern(node.Value); err != nil {
+				problem(node, "invalid emoji %q in %s: %v", node.Value, describeKey(key), err)
+			}
+		}
+		if schema.Format == formatSingleEmoji {
+			if _, err := LookupEmoji(node.Value); err != nil {
+				problem(node, "invalid emoji %q in %s: %v", node.Value, describeKey(key), err)
+			}
+		}
+		if schema.Format == formatVersion {
+			if _, _, err := parseEmojiVersion(node.Value); err != nil {
+				problem(node, "invalid emoji version %q for %s (expected a version such as 13.0)", node.Value, describeKey(key))
+			}
+		}
+		if schema.Format == formatExtends && isPresetRef(node.Value) && !contains(Presets(), node.Value) {
+			problem(node, "unknown preset %q in %s (expected one of: %s, or a path to a config file)", node.Value, describeKey(key), strings.Join(Presets(), ", "))
+		}
+	}
+}
+
+func hasType(node *yaml.Node, t string) bool {
+	switch t {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	default:
+		return node.Kind == yaml.ScalarNode && node.Tag != "!!null"
+	}
+}
+
+func describeKey(key string) string {
+	if key == "" {
+		return "the config"
+	}
+
+	return "'" + key + "'"
+}
+
+func joinKey(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
+
+func article(t string) string {
+	switch t {
+	case "object":
+		return "a mapping"
+	case "array":
+		return "a list"
+	case "integer":
+		return "an integer"
+	default:
+		return "a " + t
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConfigJSONSchema returns the JSON Schema of .emojigate.yml.
+func ConfigJSONSchema() ([]byte, error) {
+	schema := jsonSchema(configSchema())
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = SchemaURL
+	schema["title"] = "emojigate configuration"
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+func jsonSchema(s *schemaNode) map[string]any {
+	if s.Single {
+		list := *s
+		list.Single, list.Description = false, ""
+		return map[string]any{
+			"description": s.Description,
+			"anyOf":       []any{jsonSchema(s.Items), jsonSchema(&list)},
+		}
+	}
+
+	out := map[string]any{"type": s.Type}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.Minimum != nil {
+		out["minimum"] = *s.Minimum
+	}
+	if s.Enum != nil {
+		out["enum"] = s.Enum
+	}
+
+	switch s.Type {
+	case "object":
+		if s.Required != nil {
+			out["required"] = s.Required
+		}
+		if s.Properties != nil {
+			properties := map[string]any{}
+			for key, property := range s.Properties {
+				properties[key] = jsonSchema(property)
+			}
+			out["properties"] = properties
+			out["additionalProperties"] = false
+		}
+		if s.Keys != nil {
+			out["propertyNames"] = map[string]any{"enum": s.Keys}
+		}
+		if s.Values != nil {
+			out["additionalProperties"] = jsonSchema(s.Values)
+		}
+	case "array":
+		out["items"] = jsonSchema(s.Items)
+	}
+
+	return out
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"os"
+	"testing"
+)
+
+// TestValidateConfig tests that config problems are reported with their position
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expected   []string
+	}{
+		{
+			name:       "valid config",
+			configFile: "testdata/config_warnings.yml",
+		},
+		{
+			name:       "invalid config",
+			configFile: "testdata/config_invalid.yml",
+			expected: []string{
+				`2:5: invalid glob pattern "[abc" in 'ignore[0]'`,
+				`3:1: unknown key 'rulez'`,
+				`6:18: invalid value "fatal" for 'rules.require-emoji' (expected one of: error, warning, off)`,
+				`7:3: unknown rule ID "requir-emoji" in 'rules'`,
+				`8:17: 'rules.missing-name' must be a string`,
+			},
+		},
+		{
+			name:       "invalid emoji version",
+			configFile: "testdata/config_max_version_invalid.yml",
+			expected: []string{
+				`2:15: invalid emoji version "latest" for 'emoji.maxVersion' (expected a version such as 13.0)`,
+			},
+		},
+		{
+			name:       "invalid emoji entries",
+			configFile: "testdata/config_emoji_invalid.yml",
+			expected: []string{
+				`3:7: invalid emoji ":rockt:" in 'emoji.allow[0]': unknown shortcode :rockt:`,
+				`5:7: invalid emoji "Vehicles" in 'emoji.deny[0]': not an emoji, code point, shortcode or emoji group`,
+			},
+		},
+		{
+			name:       "invalid placement",
+			configFile: "testdata/config_placement_invalid.yml",
+			expected: []string{
+				`3:16: invalid value "end" for 'levels.step.placement' (expected one of: prefix, suffix, wrap, anywhere)`,
+			},
+		},
+		{
+			name:       "invalid inclusive emoji settings",
+			configFile: "testdata/config_skin_tone_invalid.yml",
+			expected: []string{
+				`2:13: invalid value "yellow" for 'emoji.skinTone' (expected one of: any, default, consistent)`,
+				`3:18: 'emoji.genderNeutral' must be a boolean`,
+			},
+		},
+		{
+			name:       "invalid semantic rules",
+			configFile: "testdata/config_semantics_invalid.yml",
+			expected: []string{
+				`4:9: missing required key 'levels.step.semantics[0].emoji'`,
+				`5:16: invalid regular expression "(unclosed" in 'levels.step.semantics[1].match'`,
+			},
+		},
+		{
+			name:       "invalid repeat settings",
+			configFile: "testdata/config_repeat_invalid.yml",
+			expected: []string{
+				`3:5: unknown key 'levels.workflow.maxRepeat'`,
+				`5:16: 'levels.step.maxRepeat' must be at least 0`,
+				`6:19: invalid emoji ":no_such_emoji:" in 'levels.step.allowRepeat[0]': unknown shortcode :no_such_emoji:`,
+			},
+		},
+		{
+			name:       "negative emoji count",
+			configFile: "testdata/config_max_emoji_invalid.yml",
+			expected: []string{
+				`3:15: 'levels.step.maxEmoji' must be at least 0`,
+			},
+		},
+		{
+			name:       "unknown preset",
+			configFile: "testdata/config_extends_invalid.yml",
+			expected: []string{
+				`2:5: unknown preset "strickt" in 'extends[0]' (expected one of: gitmoji, relaxed, steps-optional, strict, or a path to a config file)`,
+			},
+		},
+		{
+			name:       "valid single extends",
+			configFile: "testdata/shared/org.yml",
+		},
+		{
+			name:       "invalid single extends",
+			configFile: "testdata/config_extends_single_invalid.yml",
+			expected: []string{
+				`1:10: unknown preset "strickt" in 'extends' (expected one of: gitmoji, relaxed, steps-optional, strict, or a path to a config file)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.configFile)
+			if err != nil {
+				t.Fatalf("Failed to read config: %v", err)
+			}
+
+			problems, err := ValidateConfig(data)
+			if err != nil {
+				t.Fatalf("ValidateConfig() failed: %v", err)
+			}
+
+			if len(problems) != len(tt.expected) {
+				t.Fatalf("ValidateConfig() returned %d problems, want %d: %v", len(problems), len(tt.expected), problems)
+			}
+			for i, p := range problems {
+				if p.String() != tt.expected[i] {
+					t.Errorf("problem %d = %q, want %q", i, p.String(), tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestLoadConfig_RejectsInvalidConfig tests that typos never silently disable checks
+func TestLoadConfig_RejectsInvalidConfig(t *testing.T) {
+	_, err := LoadConfig("testdata/config_invalid.yml")
+
+	invalid, ok := err.(*InvalidConfigError)
+	if !ok {
+		t.Fatalf("LoadConfig() error = %v, want *InvalidConfigError", err)
+	}
+	if len(invalid.Problems) != 5 {
+		t.Errorf("Expected 5 problems, got %d", len(invalid.Problems))
+	}
+}
+
+// TestConfigJSONSchema_Published tests that the published schema matches the config schema
+func TestConfigJSONSchema_Published(t *testing.T) {
+	const publishedSchema = "../schema/emojigate.schema.json"
+
+	actual, err := ConfigJSONSchema()
+	if err != nil {
+		t.Fatalf("ConfigJSONSchema() failed: %v", err)
+	}
+
+	if os.Getenv("UPDATE_GOLDEN") == "1" {
+		if err := os.WriteFile(publishedSchema, actual, 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+		t.Logf("Updated schema: %s", publishedSchema)
+		return
+	}
+
+	expected, err := os.ReadFile(publishedSchema)
+	if err != nil {
+		t.Fatalf("Failed to read published schema: %v", err)
+	}
+
+	if string(actual) != string(expected) {
+		t.Errorf("Published schema %s is out of date.\n\nTo update: UPDATE_GOLDEN=1 go test", publishedSchema)
+	}
+}
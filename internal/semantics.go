@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// nameText returns a name without the emoji or shortcodes at its slots and
+// the separators around them, the text semantic rules are matched against:
+// "🚀 Deploy to prod" becomes "Deploy to prod".
+func nameText(name string, slots []emojiSlot) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, slot := range slots {
+		switch {
+		case slot.Shortcode != "":
+			spans = append(spans, span{slot.Offset, slot.Offset + len(slot.Shortcode)})
+		case slot.ok:
+			spans = append(spans, span{slot.Offset, slot.Offset + len(slot.Emoji.Text)})
+		}
+	}
+
+	// Cut from the end, so that earlier offsets stay valid
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+	for i, s := range spans {
+		if i > 0 && s.end > spans[i-1].start {
+			continue
+		}
+		name = name[:s.start] + name[s.end:]
+	}
+
+	return trimSeparatorLeft(trimSeparatorRight(name))
+}
+
+// emojiTexts joins emoji as written, for messages.
+func emojiTexts(emojis []Emoji) string {
+	texts := make([]string, len(emojis))
+	for i, e := range emojis {
+		texts[i] = e.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// describeEmoji lists the emoji of a semantic rule for messages, showing
+// shortcodes and code points as the emoji they stand for.
+func (r SemanticRule) describeEmoji() string {
+	described := make([]string, len(r.Emoji))
+	for i, entry := range r.Emoji {
+		described[i] = entry
+		if e, err := LookupEmoji(entry); err == nil {
+			described[i] = e.Qualified
+		}
+	}
+	return strings.Join(described, " or ")
+}
@@ -0,0 +1,54 @@
+package internal
+
+import "testing"
+
+// TestNameText tests removing the placed emoji and separators from names
+func TestNameText(t *testing.T) {
+	tests := []struct {
+		name      string
+		placement Placement
+		expected  string
+	}{
+		{"🚀 Deploy to prod", PlacementPrefix, "Deploy to prod"},
+		{"🚀 - Deploy", PlacementPrefix, "Deploy"},
+		{":rocket: Deploy", PlacementPrefix, "Deploy"},
+		{"Deploy 🚀", PlacementSuffix, "Deploy"},
+		{"🚀 Deploy 🚀", PlacementWrap, "Deploy"},
+		{"Deploy 🚀 now", PlacementAnywhere, "Deploy  now"},
+		{"🚀", PlacementWrap, ""},
+		{"Deploy", PlacementPrefix, "Deploy"},
+	}
+
+	for _, tt := range tests {
+		if got := nameText(tt.name, emojiSlots(tt.name, tt.placement)); got != tt.expected {
+			t.Errorf("nameText(%q, %s) = %q, want %q", tt.name, tt.placement, got, tt.expected)
+		}
+	}
+}
+
+// TestLintWorkflowReport_Semantics tests that the first matching semantic rule decides the emoji
+func TestLintWorkflowReport_Semantics(t *testing.T) {
+	cfg, err := LoadConfig("testdata/config_semantics.yml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	node, err := ParseYAML("testdata/semantics_workflow.yml")
+	if err != nil {
+		t.Fatalf("Failed to parse workflow: %v", err)
+	}
+
+	report, err := LintWorkflowReport(node, cfg)
+	if err != nil {
+		t.Fatalf("Linting failed: %v", err)
+	}
+
+	expected := []string{
+		"[Job] lint: Name matches (?i)^lint and must use 🧹, not 🔍 (error)",
+		"[Step] 🔧 Run tests again: Name matches (?i)test and must use 🧪 or ✅, not 🔧 (error)",
+		// The deploy rule comes first, so 🚀 or 📦 is required
+		"[Step] 🔨 Deploy after tests: Name matches (?i)deploy|release and must use 🚀 or 📦, not 🔨 (error)",
+	}
+
+	assertRuleViolations(t, report.Violations, RuleSemantic, expected)
+}